@@ -0,0 +1,136 @@
+package whatever
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// KeyTransform converts a single key. It can be one of
+// SnakeCase, CamelCase, PascalCase and KebabCase or
+// any custom function.
+type KeyTransform func(key string) string
+
+// SnakeCase converts the key to snake_case, e.g. "userID" to "user_id".
+func SnakeCase(key string) string {
+	return strings.ToLower(strings.Join(splitWords(key), "_"))
+}
+
+// KebabCase converts the key to kebab-case, e.g. "userID" to "user-id".
+func KebabCase(key string) string {
+	return strings.ToLower(strings.Join(splitWords(key), "-"))
+}
+
+// CamelCase converts the key to camelCase, e.g. "user_id" to "userId".
+func CamelCase(key string) string {
+	words := splitWords(key)
+	for i, word := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(word)
+		} else {
+			words[i] = capitalize(word)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// PascalCase converts the key to PascalCase, e.g. "user_id" to "UserId".
+func PascalCase(key string) string {
+	words := splitWords(key)
+	for i, word := range words {
+		words[i] = capitalize(word)
+	}
+	return strings.Join(words, "")
+}
+
+// TransformKeys returns a copy of the Params structure with all keys
+// converted by fn, including the keys of nested objects and of the
+// objects in slices:
+//
+//	p.TransformKeys(whatever.SnakeCase)
+//
+// Returns an error if two keys of the same object are converted to
+// the same key, because one of the values would be lost.
+// The receiver is not modified.
+func (p Params) TransformKeys(fn KeyTransform) (Params, error) {
+	result, err := transformKeys(nil, p, fn)
+	if err != nil {
+		return nil, err
+	}
+	return result.(Params), nil
+}
+
+func transformKeys(path Path, value interface{}, fn KeyTransform) (interface{}, error) {
+	if m, ok := toMap(value); ok {
+		result := make(map[string]interface{}, len(m))
+		for _, k := range sortedKeys(m) {
+			key := fn(k)
+			if _, exists := result[key]; exists {
+				return nil, fmt.Errorf("the key %s collides with another key as %s", path.Append(k), key)
+			}
+			v, err := transformKeys(path.Append(k), m[k], fn)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = v
+		}
+		if _, ok := value.(Params); ok {
+			return Params(result), nil
+		}
+		return result, nil
+	}
+
+	if s, ok := value.([]interface{}); ok {
+		result := make([]interface{}, len(s))
+		for i, el := range s {
+			v, err := transformKeys(path.Append(i), el, fn)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = v
+		}
+		return result, nil
+	}
+
+	return value, nil
+}
+
+// splitWords splits the key into words on underscores, dashes,
+// spaces, dots and case changes. A run of upper case letters is
+// treated as an acronym, so "HTTPServerID" becomes "HTTP", "Server"
+// and "ID". Digits stay with the preceding word.
+func splitWords(key string) []string {
+	var words []string
+	runes := []rune(key)
+	start := 0
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+		start = end
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-' || r == ' ' || r == '.':
+			flush(i)
+			start = i + 1
+		case unicode.IsUpper(r) && i > start:
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush(i)
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
+
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
@@ -0,0 +1,80 @@
+package whatever
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyTransforms(t *testing.T) {
+	cases := map[string][4]string{
+		// key: snake, kebab, camel, pascal
+		"userID":       {"user_id", "user-id", "userId", "UserId"},
+		"user_id":      {"user_id", "user-id", "userId", "UserId"},
+		"UserId":       {"user_id", "user-id", "userId", "UserId"},
+		"HTTPServerID": {"http_server_id", "http-server-id", "httpServerId", "HttpServerId"},
+		"kebab-case":   {"kebab_case", "kebab-case", "kebabCase", "KebabCase"},
+		"version2Name": {"version2_name", "version2-name", "version2Name", "Version2Name"},
+		"__private":    {"private", "private", "private", "Private"},
+		"simple":       {"simple", "simple", "simple", "Simple"},
+		"":             {"", "", "", ""},
+	}
+
+	for key, expected := range cases {
+		got := [4]string{SnakeCase(key), KebabCase(key), CamelCase(key), PascalCase(key)}
+		if got != expected {
+			wrong(t, "KeyTransform("+key+")", expected, got)
+		}
+	}
+}
+
+func TestParams_TransformKeys(t *testing.T) {
+	p := Params{
+		"userId": 1,
+		"profileData": map[string]interface{}{
+			"firstName": "John",
+		},
+		"recentOrders": []interface{}{
+			Params{"orderID": 1},
+			"notAnObject",
+		},
+	}
+
+	got, err := p.TransformKeys(SnakeCase)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Params{
+		"user_id": 1,
+		"profile_data": map[string]interface{}{
+			"first_name": "John",
+		},
+		"recent_orders": []interface{}{
+			Params{"order_id": 1},
+			"notAnObject",
+		},
+	}
+	if !jsonEqual(expected, got) {
+		wrong(t, "TransformKeys", expected, got)
+	}
+
+	if _, ok := got["profile_data"].(map[string]interface{}); !ok {
+		wrong(t, "TransformKeys", "map[string]interface{}", got["profile_data"])
+	}
+
+	if _, ok := p["userId"]; !ok {
+		wrong(t, "TransformKeys", "unchanged receiver", p)
+	}
+
+	upper, err := p.TransformKeys(strings.ToUpper)
+	if err != nil || upper.GetP("PROFILEDATA").Get("FIRSTNAME") != "John" {
+		wrong(t, "TransformKeys", "John", upper)
+	}
+}
+
+func TestParams_TransformKeys_collision(t *testing.T) {
+	p := Params{"nested": Params{"userId": 1, "user_id": 2}}
+	if _, err := p.TransformKeys(SnakeCase); err == nil {
+		wrong(t, "TransformKeys", "an error", nil)
+	}
+}
@@ -0,0 +1,362 @@
+package whatever
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Source supplies one layer of a Config. Name identifies the
+// source in the errors and in the origins of the keys.
+type Source interface {
+	Name() string
+	Load() (Params, error)
+}
+
+type sourceFunc struct {
+	name string
+	load func() (Params, error)
+}
+
+func (s sourceFunc) Name() string          { return s.name }
+func (s sourceFunc) Load() (Params, error) { return s.load() }
+
+// SourceFunc returns a Source with the given name
+// that loads its Params with fn.
+func SourceFunc(name string, fn func() (Params, error)) Source {
+	return sourceFunc{name: name, load: fn}
+}
+
+// StaticSource returns a Source that always loads a copy of p.
+// It is useful for the defaults of a Config.
+func StaticSource(name string, p Params) Source {
+	return SourceFunc(name, func() (Params, error) {
+		return copyValue(p).(Params), nil
+	})
+}
+
+// FileSource returns a Source that reads the file at path and
+// decodes it by its extension: .json, .ini, .env or one
+// registered with RegisterFormat. The name of the source is
+// "file:" followed by the path.
+func FileSource(path string) Source {
+	return fileSource(path)
+}
+
+type fileSource string
+
+func (s fileSource) Name() string { return "file:" + string(s) }
+func (s fileSource) Path() string { return string(s) }
+
+func (s fileSource) Load() (Params, error) {
+	body, err := os.ReadFile(string(s))
+	if err != nil {
+		return nil, err
+	}
+
+	ext := strings.ToLower(filepath.Ext(string(s)))
+	formatsMu.RLock()
+	decode, ok := formats[ext]
+	formatsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported file extension %q", ext)
+	}
+	return decode(body)
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]func([]byte) (Params, error){
+		".json": NewFromJSON,
+		".ini":  NewFromINI,
+		".env":  NewFromDotenv,
+	}
+)
+
+// RegisterFormat registers decode for the files with the given
+// extension, e.g. ".yaml", replacing any decoder for it. The
+// extensions are case-insensitive. The formats with third-party
+// dependencies live in subpackages that register themselves
+// when imported:
+//
+//	import _ "github.com/ndyakov/whatever/yaml"
+func RegisterFormat(ext string, decode func([]byte) (Params, error)) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[strings.ToLower(ext)] = decode
+}
+
+// EnvSource returns a Source that loads the environment
+// variables with NewFromEnv. The name of the source is
+// "env:" followed by the prefix.
+func EnvSource(prefix, sep string, opts ...EnvOption) Source {
+	return SourceFunc("env:"+prefix, func() (Params, error) {
+		return NewFromEnv(prefix, sep, opts...), nil
+	})
+}
+
+// Optional wraps a Source so that a missing file
+// loads as empty Params instead of an error.
+func Optional(s Source) Source {
+	return optionalSource{s}
+}
+
+type optionalSource struct {
+	Source
+}
+
+func (s optionalSource) Load() (Params, error) {
+	p, err := s.Source.Load()
+	if errors.Is(err, fs.ErrNotExist) {
+		return Params{}, nil
+	}
+	return p, err
+}
+
+func (s optionalSource) Path() string {
+	return sourcePath(s.Source)
+}
+
+// sourcePath returns the path of the file
+// read by a FileSource or an empty string.
+func sourcePath(s Source) string {
+	if f, ok := s.(interface{ Path() string }); ok {
+		return f.Path()
+	}
+	return ""
+}
+
+// Config assembles Params from a stack of sources. The sources
+// are loaded in the order they were added and every source is
+// deep-merged over the previous ones, so the later sources take
+// precedence:
+//
+//	config := whatever.NewConfig(
+//		whatever.StaticSource("defaults", defaults),
+//		whatever.Optional(whatever.FileSource("config.json")),
+//		whatever.EnvSource("APP_", "__"),
+//	).Require("db.host")
+//	p, err := config.Load()
+//
+// Nested Params are merged key by key, all other values
+// (including slices) replace the previous ones, unless they
+// are index-keyed overrides of slice elements as described
+// in FlagSource. Config
+// remembers which source supplied every key, see Origin
+// and Print. It is safe for concurrent use, so sources can be
+// added while the Config is watched.
+type Config struct {
+	mu       sync.RWMutex
+	sources  []Source
+	required []string
+	params   Params
+	origins  map[string]string
+}
+
+// NewConfig returns a Config with the given sources,
+// from the lowest to the highest precedence.
+func NewConfig(sources ...Source) *Config {
+	return &Config{sources: sources}
+}
+
+// Add adds sources with higher precedence than
+// the existing ones and returns the Config.
+func (c *Config) Add(sources ...Source) *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sources = append(c.sources, sources...)
+	return c
+}
+
+// Require sets the keys in dotted notation that
+// are validated with Required after every Load.
+func (c *Config) Require(keys ...string) *Config {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.required = append(c.required, keys...)
+	return c
+}
+
+// stack returns copies of the sources and the required keys,
+// so they can be used without holding the lock.
+func (c *Config) stack() ([]Source, []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Source(nil), c.sources...), append([]string(nil), c.required...)
+}
+
+// Load loads and merges all sources and validates the result.
+// The errors are prefixed with the name of the failing source.
+// If there is an error, the previously loaded Params are kept.
+// The result is a deep copy that can be modified freely.
+func (c *Config) Load() (Params, error) {
+	p, origins, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+
+	c.swap(p, origins)
+	return copyValue(p).(Params), nil
+}
+
+func (c *Config) swap(p Params, origins map[string]string) {
+	c.mu.Lock()
+	c.params, c.origins = p, origins
+	c.mu.Unlock()
+}
+
+func (c *Config) load() (Params, map[string]string, error) {
+	sources, required := c.stack()
+	p := Params{}
+	origins := map[string]string{}
+	for _, source := range sources {
+		layer, err := source.Load()
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", source.Name(), err)
+		}
+		deepMerge(p, layer, "", source.Name(), origins)
+	}
+
+	if err := p.Required(required...); err != nil {
+		return nil, nil, err
+	}
+	return p, origins, nil
+}
+
+// Params returns a deep copy of the Params from the last
+// successful Load, or nil if nothing was loaded yet.
+func (c *Config) Params() Params {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.params == nil {
+		return nil
+	}
+	return copyValue(c.params).(Params)
+}
+
+// Origin returns the name of the source that supplied the value
+// under the dotted key in the last successful Load. The keys
+// nested in a value that was set as a whole share its origin.
+func (c *Config) Origin(key string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.origin(key)
+}
+
+func (c *Config) origin(key string) (string, bool) {
+	if _, err := c.params.Pointer(DottedToPointer(key)); err != nil {
+		return "", false
+	}
+
+	for {
+		if name, ok := c.origins[key]; ok {
+			return name, true
+		}
+
+		index := strings.LastIndex(key, ".")
+		if index == -1 {
+			return "", false
+		}
+		key = key[:index]
+	}
+}
+
+// Print writes every leaf value of the last successful
+// Load with its origin, one per line, sorted by key:
+//
+//	db.host = "localhost" # env:APP_
+//
+// It is meant for --print-config style debugging.
+func (c *Config) Print(w io.Writer) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var buf bytes.Buffer
+	c.params.Walk(func(path Path, value interface{}) error {
+		if m, ok := toMap(value); ok && len(m) > 0 {
+			return nil
+		}
+
+		key := path.String()
+		origin, _ := c.origin(key)
+		fmt.Fprintf(&buf, "%s = %s # %s\n", key, encodeValue(value), origin)
+		if _, ok := value.([]interface{}); ok {
+			return SkipDir
+		}
+		return nil
+	})
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// deepMerge merges src into dst recursively and records the name
+// of the source for every value it sets in origins. A value set as
+// a whole drops the origins of the values it replaces. A map whose
+// keys are all indexes of a slice in dst, as built by FlagSource
+// for -servers.0.host, is merged into the elements of the slice.
+func deepMerge(dst, src map[string]interface{}, prefix, name string, origins map[string]string) {
+	for k, v := range src {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		if sm, ok := toMap(v); ok {
+			if dm, ok := toMap(dst[k]); ok {
+				deepMerge(dm, sm, key, name, origins)
+				continue
+			}
+			if ds, ok := dst[k].([]interface{}); ok && indexKeys(sm, len(ds)) {
+				mergeElements(ds, sm, key, name, origins)
+				continue
+			}
+		}
+
+		for origin := range origins {
+			if strings.HasPrefix(origin, key+".") {
+				delete(origins, origin)
+			}
+		}
+		dst[k] = copyValue(v)
+		origins[key] = name
+	}
+}
+
+// indexKeys reports whether m has keys and all of them
+// are indexes of a slice with the given length.
+func indexKeys(m map[string]interface{}, length int) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for k := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= length || strconv.Itoa(i) != k {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeElements merges the index-keyed src into the
+// elements of the slice dst as deepMerge merges maps.
+func mergeElements(dst []interface{}, src map[string]interface{}, prefix, name string, origins map[string]string) {
+	elements := make(map[string]interface{}, len(src))
+	for k := range src {
+		i, _ := strconv.Atoi(k)
+		elements[k] = dst[i]
+	}
+
+	deepMerge(elements, src, prefix, name, origins)
+	for k, v := range elements {
+		i, _ := strconv.Atoi(k)
+		dst[i] = v
+	}
+}
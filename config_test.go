@@ -0,0 +1,225 @@
+package whatever
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConfigLoad(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(file, []byte(`{"db": {"host": "db.local", "user": "app"}, "hosts": ["a", "b"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := Params{"db": Params{"host": "localhost", "port": 5432}, "debug": false}
+	config := NewConfig(
+		StaticSource("defaults", defaults),
+		FileSource(file),
+		Optional(FileSource(filepath.Join(dir, "missing.toml"))),
+	).Add(SourceFunc("flags", func() (Params, error) {
+		return Params{"db": Params{"user": "root"}, "debug": true}, nil
+	})).Require("db.host", "db.port")
+
+	p, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Params{
+		"db":    Params{"host": "db.local", "port": 5432, "user": "root"},
+		"debug": true,
+		"hosts": []interface{}{"a", "b"},
+	}
+	if !Equal(expected, p) {
+		wrong(t, "Config.Load", expected, p)
+	}
+
+	if defaults.GetP("db").GetString("host") != "localhost" {
+		t.Error("Config.Load modified the defaults")
+	}
+
+	origins := map[string]string{
+		"db.host": "file:" + file,
+		"db.port": "defaults",
+		"db.user": "flags",
+		"debug":   "flags",
+		"hosts":   "file:" + file,
+	}
+	for key, name := range origins {
+		if got, _ := config.Origin(key); got != name {
+			wrong(t, "Config.Origin "+key, name, got)
+		}
+	}
+
+	if _, ok := config.Origin("missing"); ok {
+		t.Error("Config.Origin expected no origin for a missing key")
+	}
+
+	var buf bytes.Buffer
+	if err := config.Print(&buf); err != nil {
+		t.Fatal(err)
+	}
+	printed := strings.Join([]string{
+		`db.host = "db.local" # file:` + file,
+		`db.port = 5432 # defaults`,
+		`db.user = "root" # flags`,
+		`debug = true # flags`,
+		`hosts = ["a","b"] # file:` + file,
+		``,
+	}, "\n")
+	if buf.String() != printed {
+		wrong(t, "Config.Print", printed, buf.String())
+	}
+}
+
+func TestConfigLoadOverride(t *testing.T) {
+	config := NewConfig(
+		StaticSource("first", Params{"db": Params{"host": "a", "port": 1}}),
+		StaticSource("second", Params{"db": "plain"}),
+		StaticSource("third", Params{"db": Params{"host": "b"}}),
+	)
+
+	p, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !Equal(Params{"db": Params{"host": "b"}}, p) {
+		wrong(t, "Config.Load", Params{"db": Params{"host": "b"}}, p)
+	}
+
+	if got, _ := config.Origin("db.port"); got != "" {
+		wrong(t, "Config.Origin", "", got)
+	}
+}
+
+func TestConfigLoadErrors(t *testing.T) {
+	failure := errors.New("failure")
+	config := NewConfig(
+		StaticSource("defaults", Params{"name": "example"}),
+	).Require("name")
+
+	if _, err := config.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	config.Require("db.host")
+	if _, err := config.Load(); err == nil || err.Error() != "the parameter db.host is required" {
+		wrong(t, "Config.Load", "the parameter db.host is required", err)
+	}
+
+	if config.Params().GetString("name") != "example" {
+		t.Error("Config.Load should keep the previous Params on error")
+	}
+
+	config = NewConfig(SourceFunc("broken", func() (Params, error) { return nil, failure }))
+	if _, err := config.Load(); !errors.Is(err, failure) || err.Error() != "broken: failure" {
+		wrong(t, "Config.Load", "broken: failure", err)
+	}
+
+	config = NewConfig(FileSource("missing.json"))
+	if _, err := config.Load(); err == nil {
+		t.Error("Config.Load expected an error for a missing file")
+	}
+
+	config = NewConfig(FileSource("config.xml"))
+	if _, err := config.Load(); err == nil {
+		t.Error("Config.Load expected an error for an unsupported file")
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.LIST")
+	if err := os.WriteFile(file, []byte("a,b"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := FileSource(file).Load(); err == nil || err.Error() != `unsupported file extension ".list"` {
+		wrong(t, "FileSource", `unsupported file extension ".list"`, err)
+	}
+
+	t.Cleanup(func() {
+		formatsMu.Lock()
+		delete(formats, ".list")
+		formatsMu.Unlock()
+	})
+	RegisterFormat(".List", func(body []byte) (Params, error) {
+		var items []interface{}
+		for _, item := range strings.Split(string(body), ",") {
+			items = append(items, item)
+		}
+		return Params{"items": items}, nil
+	})
+	p, err := FileSource(file).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.GetSliceStrings("items"); !equalSlicesStrings([]string{"a", "b"}, got) {
+		wrong(t, "FileSource", []string{"a", "b"}, got)
+	}
+}
+
+func TestConfigReturnsCopies(t *testing.T) {
+	config := NewConfig(StaticSource("defaults", Params{"db": Params{"host": "localhost"}}))
+	p, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.GetP("db")["host"] = "changed"
+	config.Params().GetP("db")["host"] = "changed"
+	if got := config.Params().GetP("db").GetString("host"); got != "localhost" {
+		wrong(t, "Config.Params", "localhost", got)
+	}
+	if origin, _ := config.Origin("db.host"); origin != "defaults" {
+		wrong(t, "Config.Origin", "defaults", origin)
+	}
+}
+
+func TestOptionalWrappedNotExist(t *testing.T) {
+	source := Optional(SourceFunc("remote", func() (Params, error) {
+		return nil, fmt.Errorf("fetching config: %w", fs.ErrNotExist)
+	}))
+
+	p, err := source.Load()
+	if err != nil || !p.Empty() {
+		wrong(t, "Optional", Params{}, err)
+	}
+}
+
+func TestConfigConcurrentAdd(t *testing.T) {
+	config := NewConfig(StaticSource("defaults", Params{"name": "example"}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key%d", i)
+			config.Add(StaticSource(key, Params{key: i})).Require("name")
+		}(i)
+		go func() {
+			defer wg.Done()
+			if _, err := config.Load(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	p, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p) != 5 {
+		wrong(t, "Config.Load", "5 keys", p)
+	}
+}
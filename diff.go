@@ -0,0 +1,244 @@
+package whatever
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ChangeType is the kind of a Change.
+type ChangeType string
+
+const (
+	// Added is a value that is present only in the new structure.
+	Added ChangeType = "added"
+	// Removed is a value that is present only in the old structure.
+	Removed ChangeType = "removed"
+	// Changed is a value that is different in the two structures.
+	Changed ChangeType = "changed"
+)
+
+// Change is a single difference between two Params structures.
+// Old is nil for Added and New is nil for Removed changes.
+type Change struct {
+	Type ChangeType
+	Path Path
+	Old  interface{}
+	New  interface{}
+
+	// unmatched marks the elements without an equal
+	// counterpart when the slice order is ignored.
+	unmatched bool
+}
+
+// Changes is the list of differences returned by Compare.
+// It can be rendered as text (String), JSON (MarshalJSON)
+// or a unified-diff-like view (Unified), and converted to
+// a JSON Patch (Patch).
+type Changes []Change
+
+// Compare returns the differences between the old and the new
+// Params structure. Objects are compared recursively and slices
+// element by element. Nested Params and map[string]interface{}
+// with the same contents are the same and numbers are compared
+// by value, as with Equal and NormalizeNumbers. The changes are
+// in deterministic order.
+func Compare(old, new Params) Changes {
+	o := equalOptions{normalizeNumbers: true}
+	return o.compare(nil, rootMap(old), rootMap(new), nil)
+}
+
+// rootMap returns p as map[string]interface{},
+// so a nil Params is compared as an empty one.
+func rootMap(p Params) map[string]interface{} {
+	if p == nil {
+		return map[string]interface{}{}
+	}
+	return p
+}
+
+// compare appends the changes between a and b under the path.
+// It is the single walk behind both Compare and Equal.
+func (o *equalOptions) compare(path Path, a, b interface{}, changes Changes) Changes {
+	if len(path) > 0 && matchAny(path, o.ignore) {
+		return changes
+	}
+
+	if ma, ok := toMap(a); ok {
+		if mb, ok := toMap(b); ok {
+			return o.compareObjects(path, ma, mb, changes)
+		}
+	}
+
+	if sa, ok := a.([]interface{}); ok {
+		if sb, ok := b.([]interface{}); ok {
+			if o.ignoreSliceOrder {
+				return o.compareUnordered(path, sa, sb, changes)
+			}
+			return o.compareElements(path, sa, sb, changes)
+		}
+	}
+
+	if !o.equalLeaves(a, b) {
+		changes = append(changes, Change{Type: Changed, Path: path, Old: copyValue(a), New: copyValue(b)})
+	}
+	return changes
+}
+
+func (o *equalOptions) compareObjects(path Path, a, b map[string]interface{}, changes Changes) Changes {
+	present := func(m map[string]interface{}, k string) bool {
+		v, ok := m[k]
+		return ok && !(o.nilAsMissing && v == nil)
+	}
+
+	for _, k := range sortedKeys(a) {
+		if present(a, k) && !present(b, k) && !matchAny(path.Append(k), o.ignore) {
+			changes = append(changes, Change{Type: Removed, Path: path.Append(k), Old: copyValue(a[k])})
+		}
+	}
+
+	for _, k := range sortedKeys(b) {
+		switch {
+		case !present(b, k):
+		case present(a, k):
+			changes = o.compare(path.Append(k), a[k], b[k], changes)
+		case !matchAny(path.Append(k), o.ignore):
+			changes = append(changes, Change{Type: Added, Path: path.Append(k), New: copyValue(b[k])})
+		}
+	}
+	return changes
+}
+
+func (o *equalOptions) compareElements(path Path, a, b []interface{}, changes Changes) Changes {
+	common := len(a)
+	if len(b) < common {
+		common = len(b)
+	}
+
+	for i := 0; i < common; i++ {
+		changes = o.compare(path.Append(i), a[i], b[i], changes)
+	}
+
+	// The removed elements are listed from the end,
+	// so the indexes stay valid when they are applied in order.
+	for i := len(a) - 1; i >= common; i-- {
+		if !matchAny(path.Append(i), o.ignore) {
+			changes = append(changes, Change{Type: Removed, Path: path.Append(i), Old: copyValue(a[i])})
+		}
+	}
+
+	for i := common; i < len(b); i++ {
+		if !matchAny(path.Append(i), o.ignore) {
+			changes = append(changes, Change{Type: Added, Path: path.Append(i), New: copyValue(b[i])})
+		}
+	}
+	return changes
+}
+
+// compareUnordered matches every element of a with an equal
+// element of b. The elements without a match are reported as
+// removed from a or added to b.
+func (o *equalOptions) compareUnordered(path Path, a, b []interface{}, changes Changes) Changes {
+	used := make([]bool, len(b))
+	for i, va := range a {
+		found := false
+		for j, vb := range b {
+			if !used[j] && len(o.compare(path.Append(i), va, vb, nil)) == 0 {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			changes = append(changes, Change{Type: Removed, Path: path.Append(i), Old: copyValue(va), unmatched: true})
+		}
+	}
+
+	for j, vb := range b {
+		if !used[j] {
+			changes = append(changes, Change{Type: Added, Path: path.Append(j), New: copyValue(vb), unmatched: true})
+		}
+	}
+	return changes
+}
+
+// Patch converts the changes to a JSON Patch with add,
+// remove and replace operations.
+func (c Changes) Patch() Patch {
+	patch := make(Patch, 0, len(c))
+	for _, change := range c {
+		op := Operation{Path: change.Path.Pointer()}
+		switch change.Type {
+		case Added:
+			op.Op, op.Value = "add", change.New
+		case Removed:
+			op.Op = "remove"
+		case Changed:
+			op.Op, op.Value = "replace", change.New
+		}
+		patch = append(patch, op)
+	}
+	return patch
+}
+
+// String renders the changes as text, one per line. The added
+// values are prefixed with "+", the removed with "-" and the
+// changed with "~", followed by the old and the new value:
+//
+//	~ changed.key: "old" -> "new"
+func (c Changes) String() string {
+	var buf bytes.Buffer
+	for _, change := range c {
+		switch change.Type {
+		case Added:
+			fmt.Fprintf(&buf, "+ %s: %s\n", pathName(change.Path), encodeValue(change.New))
+		case Removed:
+			fmt.Fprintf(&buf, "- %s: %s\n", pathName(change.Path), encodeValue(change.Old))
+		case Changed:
+			fmt.Fprintf(&buf, "~ %s: %s -> %s\n", pathName(change.Path), encodeValue(change.Old), encodeValue(change.New))
+		}
+	}
+	return buf.String()
+}
+
+// Unified renders the changes in a view that resembles
+// a unified diff. Every changed value is listed with
+// its old value on a "-" line and the new on a "+" line:
+//
+//	--- old
+//	+++ new
+//	- key: "old"
+//	+ key: "new"
+func (c Changes) Unified() string {
+	var buf bytes.Buffer
+	buf.WriteString("--- old\n+++ new\n")
+	for _, change := range c {
+		if change.Type != Added {
+			fmt.Fprintf(&buf, "- %s: %s\n", pathName(change.Path), encodeValue(change.Old))
+		}
+		if change.Type != Removed {
+			fmt.Fprintf(&buf, "+ %s: %s\n", pathName(change.Path), encodeValue(change.New))
+		}
+	}
+	return buf.String()
+}
+
+// MarshalJSON encodes the change as a JSON object with
+// the type, the path as a JSON Pointer and the values.
+func (c Change) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{"type": c.Type, "path": c.Path.Pointer()}
+	if c.Type != Added {
+		m["old"] = c.Old
+	}
+	if c.Type != Removed {
+		m["new"] = c.New
+	}
+	return json.Marshal(m)
+}
+
+func encodeValue(v interface{}) string {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(encoded)
+}
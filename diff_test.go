@@ -0,0 +1,94 @@
+package whatever
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var (
+	compareOld = parse([]byte(`{
+		"name": "old",
+		"removed": true,
+		"list": [1, 2, 3],
+		"nested": {"one": 1, "two": 2}
+	}`))
+	compareNew = Params{
+		"name":   "new",
+		"added":  Params{"x": 1},
+		"list":   []interface{}{1, 2},
+		"nested": map[string]interface{}{"one": 1, "two": 2},
+	}
+)
+
+func TestCompare(t *testing.T) {
+	changes := Compare(compareOld, compareNew)
+
+	expected := Changes{
+		{Type: Removed, Path: Path{"removed"}, Old: true},
+		{Type: Added, Path: Path{"added"}, New: Params{"x": 1}},
+		{Type: Removed, Path: Path{"list", 2}, Old: 3.0},
+		{Type: Changed, Path: Path{"name"}, Old: "old", New: "new"},
+	}
+
+	got, _ := json.Marshal(changes)
+	want, _ := json.Marshal(expected)
+	if string(got) != string(want) {
+		wrong(t, "Compare", string(want), string(got))
+	}
+
+	if changes := Compare(compareOld, compareOld); len(changes) != 0 {
+		wrong(t, "Compare", Changes{}, changes)
+	}
+}
+
+func TestChanges_String(t *testing.T) {
+	expected := `- removed: true
++ added: {"x":1}
+- list.2: 3
+~ name: "old" -> "new"
+`
+	if got := Compare(compareOld, compareNew).String(); got != expected {
+		wrong(t, "Changes.String", expected, got)
+	}
+}
+
+func TestChanges_Unified(t *testing.T) {
+	expected := `--- old
++++ new
+- removed: true
++ added: {"x":1}
+- list.2: 3
+- name: "old"
++ name: "new"
+`
+	if got := Compare(compareOld, compareNew).Unified(); got != expected {
+		wrong(t, "Changes.Unified", expected, got)
+	}
+}
+
+func TestChanges_MarshalJSON(t *testing.T) {
+	expected := `[{"old":true,"path":"/removed","type":"removed"},` +
+		`{"new":{"x":1},"path":"/added","type":"added"},` +
+		`{"old":3,"path":"/list/2","type":"removed"},` +
+		`{"new":"new","old":"old","path":"/name","type":"changed"}]`
+
+	got, err := json.Marshal(Compare(compareOld, compareNew))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != expected {
+		wrong(t, "Changes.MarshalJSON", expected, string(got))
+	}
+}
+
+func TestChanges_Patch(t *testing.T) {
+	p := copyValue(compareOld).(Params)
+	if err := p.ApplyPatch(Compare(compareOld, compareNew).Patch()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !jsonEqual(compareNew, p) {
+		wrong(t, "Changes.Patch", compareNew, p)
+	}
+}
@@ -20,4 +20,11 @@
 //
 // If you need you can validate the existence of a specific key by
 // using the Required method.
+//
+// If the same Params has to be shared between goroutines you can
+// wrap it in SyncParams which guards all methods with a RWMutex.
+//
+// Configuration assembled from defaults, files, environment and
+// flags can be loaded with Config, which deep-merges the sources
+// in order of precedence and remembers where every key came from.
 package whatever
@@ -0,0 +1,177 @@
+package whatever
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NewFromDotenv receives a slice of bytes that should be a .env
+// file and returns Params structure with a top-level key for every
+// variable and an error if there was one while parsing. It supports:
+//
+//	# comments and empty lines
+//	export KEY=value       the export prefix is ignored
+//	KEY=value # comment    unquoted values are trimmed
+//	KEY='literal $value'   no escapes and no expansion
+//	KEY="line\nbreak"      escapes (\n, \r, \t, \", \\, \$)
+//	KEY="multi
+//	line"                  quoted values can span lines
+//	KEY=${OTHER:-default}  expansion of $VAR, ${VAR} and ${VAR:-default}
+//
+// The variables are expanded with the values defined earlier in
+// the file and then with the environment of the process.
+func NewFromDotenv(dotenvBody []byte) (Params, error) {
+	p := Params{}
+	resolve := func(name string) (string, bool) {
+		if v, ok := p[name]; ok {
+			return v.(string), true
+		}
+		return os.LookupEnv(name)
+	}
+
+	lines := strings.Split(strings.Replace(string(dotenvBody), "\r\n", "\n", -1), "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "export ") {
+			line = strings.TrimSpace(line[len("export "):])
+		}
+
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNo)
+		}
+
+		key := strings.TrimSpace(line[:eq])
+		if !validEnvName(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNo, key)
+		}
+
+		raw := strings.TrimSpace(line[eq+1:])
+		var value string
+		switch {
+		case strings.HasPrefix(raw, "'") || strings.HasPrefix(raw, `"`):
+			quote := raw[0]
+			// Join the following lines until the closing quote.
+			for closingDotenvQuote(raw, quote) == -1 && i+1 < len(lines) {
+				i++
+				raw += "\n" + lines[i]
+			}
+			end := closingDotenvQuote(raw, quote)
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: the quoted value is not closed", lineNo)
+			}
+			if rest := strings.TrimSpace(raw[end+1:]); rest != "" && rest[0] != '#' {
+				return nil, fmt.Errorf("line %d: unexpected %q after the quoted value", lineNo, rest)
+			}
+			if quote == '\'' {
+				value = raw[1:end]
+			} else {
+				value = expandEnv(raw[1:end], true, resolve)
+			}
+		default:
+			if index := strings.Index(raw, " #"); index != -1 {
+				raw = strings.TrimSpace(raw[:index])
+			}
+			value = expandEnv(raw, false, resolve)
+		}
+
+		p[key] = value
+	}
+
+	return p, nil
+}
+
+func validEnvName(name string) bool {
+	for i, r := range name {
+		isLetter := r == '_' || r == '.' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+		if !isLetter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+func closingDotenvQuote(s string, quote byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// expandEnv replaces $VAR, ${VAR} and ${VAR:-default} in s with
+// the values returned by resolve. Missing variables without a
+// default become empty strings. If escapes is true, the escape
+// sequences of double quoted values are processed as well.
+func expandEnv(s string, escapes bool, resolve func(string) (string, bool)) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && escapes && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				buf.WriteByte('\n')
+			case 'r':
+				buf.WriteByte('\r')
+			case 't':
+				buf.WriteByte('\t')
+			case '"', '\\', '$':
+				buf.WriteByte(s[i])
+			default:
+				buf.WriteByte('\\')
+				buf.WriteByte(s[i])
+			}
+			continue
+		}
+
+		if c != '$' || i+1 >= len(s) {
+			buf.WriteByte(c)
+			continue
+		}
+
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i:], '}')
+			if end == -1 {
+				buf.WriteByte(c)
+				continue
+			}
+			expr := s[i+2 : i+end]
+			name, fallback, hasFallback := expr, "", false
+			if index := strings.Index(expr, ":-"); index != -1 {
+				name, fallback, hasFallback = expr[:index], expr[index+2:], true
+			}
+			value, ok := resolve(name)
+			if (!ok || value == "") && hasFallback {
+				value = expandEnv(fallback, false, resolve)
+			}
+			buf.WriteString(value)
+			i += end
+			continue
+		}
+
+		end := i + 1
+		for end < len(s) && (s[end] == '_' || (s[end] >= 'a' && s[end] <= 'z') ||
+			(s[end] >= 'A' && s[end] <= 'Z') || (s[end] >= '0' && s[end] <= '9')) {
+			end++
+		}
+		if end == i+1 {
+			buf.WriteByte(c)
+			continue
+		}
+		value, _ := resolve(s[i+1 : end])
+		buf.WriteString(value)
+		i = end - 1
+	}
+	return buf.String()
+}
@@ -0,0 +1,57 @@
+package whatever
+
+import (
+	"os"
+	"testing"
+)
+
+var dotenvBody = []byte(`
+# database settings
+export DB_HOST=localhost # comment
+DB_PORT=5432
+DB_URL="postgres://${DB_HOST}:$DB_PORT/app"
+LITERAL='no $DB_HOST here'
+ESCAPED="tab\there \$DB_HOST"
+MULTI="first
+second"
+FALLBACK=${MISSING_VARIABLE:-default}
+FROM_ENV=${WHATEVER_DOTENV_TEST}
+`)
+
+func TestNewFromDotenv(t *testing.T) {
+	os.Setenv("WHATEVER_DOTENV_TEST", "process")
+	defer os.Unsetenv("WHATEVER_DOTENV_TEST")
+
+	p, err := NewFromDotenv(dotenvBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"DB_HOST":  "localhost",
+		"DB_PORT":  "5432",
+		"DB_URL":   "postgres://localhost:5432/app",
+		"LITERAL":  "no $DB_HOST here",
+		"ESCAPED":  "tab\there $DB_HOST",
+		"MULTI":    "first\nsecond",
+		"FALLBACK": "default",
+		"FROM_ENV": "process",
+	}
+	for key, value := range expected {
+		if got := p.GetString(key); got != value {
+			wrong(t, "NewFromDotenv "+key, value, got)
+		}
+	}
+
+	if len(p) != len(expected) {
+		wrong(t, "NewFromDotenv", len(expected), len(p))
+	}
+}
+
+func TestNewFromDotenvErrors(t *testing.T) {
+	for _, body := range []string{"NOVALUE", "=value", "1KEY=value", `KEY="open`, `KEY="a" b`} {
+		if _, err := NewFromDotenv([]byte(body)); err == nil {
+			t.Errorf("NewFromDotenv(%q) expected an error", body)
+		}
+	}
+}
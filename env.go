@@ -0,0 +1,190 @@
+package whatever
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvOption configures NewFromEnv.
+type EnvOption func(*envOptions)
+
+type envOptions struct {
+	listSeparator string
+	inferTypes    bool
+}
+
+// SplitLists splits the values that contain sep into slices,
+// so APP_HOSTS=a,b becomes {"hosts": ["a", "b"]}. The elements
+// are trimmed. An empty sep means ",".
+func SplitLists(sep string) EnvOption {
+	return func(o *envOptions) {
+		if sep == "" {
+			sep = ","
+		}
+		o.listSeparator = sep
+	}
+}
+
+// InferTypes converts the values that look like integers,
+// floats or booleans to int, float64 and bool. Only plain
+// decimal floats, optionally with an exponent, are converted,
+// so values like "inf" or "NaN" stay strings, as does
+// everything else.
+func InferTypes() EnvOption {
+	return func(o *envOptions) {
+		o.inferTypes = true
+	}
+}
+
+// NewFromEnv returns Params structure with the environment
+// variables starting with prefix. The prefix is removed, the
+// rest of the name is lowercased and split by sep into nested
+// keys, so with
+//
+//	APP_DB__HOST=localhost
+//
+// NewFromEnv("APP_", "__") returns {"db": {"host": "localhost"}}.
+// An empty sep disables the nesting. If a variable is both a value
+// and a parent of other variables (APP_DB and APP_DB__HOST), the
+// nested keys win. The values are strings unless SplitLists or
+// InferTypes is given.
+func NewFromEnv(prefix, sep string, opts ...EnvOption) Params {
+	return fromEnviron(os.Environ(), prefix, sep, opts...)
+}
+
+func fromEnviron(environ []string, prefix, sep string, opts ...EnvOption) Params {
+	var o envOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	type variable struct {
+		path  []string
+		value string
+	}
+
+	var variables []variable
+	for _, kv := range environ {
+		eq := strings.IndexByte(kv, '=')
+		if eq == -1 || !strings.HasPrefix(kv[:eq], prefix) || eq == len(prefix) {
+			continue
+		}
+
+		name := strings.ToLower(kv[len(prefix):eq])
+		var path []string
+		if sep == "" {
+			path = []string{name}
+		} else {
+			path = strings.Split(name, strings.ToLower(sep))
+		}
+		variables = append(variables, variable{path, kv[eq+1:]})
+	}
+
+	// The variables are stored from the shallowest to the deepest,
+	// so the nested keys replace the values of their parents.
+	sort.SliceStable(variables, func(i, j int) bool {
+		if len(variables[i].path) != len(variables[j].path) {
+			return len(variables[i].path) < len(variables[j].path)
+		}
+		return strings.Join(variables[i].path, ".") < strings.Join(variables[j].path, ".")
+	})
+
+	p := Params{}
+	for _, v := range variables {
+		store(p, strings.Join(v.path, "."), envValue(v.value, o))
+	}
+	return p
+}
+
+func envValue(raw string, o envOptions) interface{} {
+	if o.listSeparator != "" && strings.Contains(raw, o.listSeparator) {
+		parts := strings.Split(raw, o.listSeparator)
+		result := make([]interface{}, len(parts))
+		for i, part := range parts {
+			result[i] = envScalar(strings.TrimSpace(part), o)
+		}
+		return result
+	}
+	return envScalar(raw, o)
+}
+
+// envFloat matches the plain decimal floats. ParseFloat alone
+// accepts "inf", "NaN" and hexadecimal floats as well.
+var envFloat = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+func envScalar(raw string, o envOptions) interface{} {
+	if !o.inferTypes {
+		return raw
+	}
+
+	if i, err := strconv.Atoi(raw); err == nil {
+		return i
+	}
+	if envFloat.MatchString(raw) {
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f
+		}
+	}
+	if raw == "true" || raw == "false" {
+		return raw == "true"
+	}
+	return raw
+}
+
+// ToEnv returns the values in the Params structure as
+// environment assignments in the form NAME=value, sorted by
+// name. The names are the prefix followed by the uppercased
+// nested keys joined with sep:
+//
+//	whatever.Params{"db": whatever.Params{"host": "localhost"}}.ToEnv("APP_", "__")
+//	// [APP_DB__HOST=localhost]
+//
+// Slices of simple values are joined with "," and slices that
+// contain maps or slices use the indexes as keys. This is the
+// environment counterpart of URLValues.
+func (p Params) ToEnv(prefix, sep string) []string {
+	var result []string
+	p.Walk(func(path Path, value interface{}) error {
+		switch val := value.(type) {
+		case Params, map[string]interface{}:
+			return nil
+		case []interface{}:
+			if !simpleSlice(val) {
+				return nil
+			}
+			parts := make([]string, len(val))
+			for i, el := range val {
+				parts[i] = stringify(el)
+			}
+			result = append(result, envAssignment(prefix, sep, path, strings.Join(parts, ",")))
+			return SkipDir
+		}
+
+		result = append(result, envAssignment(prefix, sep, path, stringify(value)))
+		return nil
+	})
+	sort.Strings(result)
+	return result
+}
+
+func envAssignment(prefix, sep string, path Path, value string) string {
+	parts := make([]string, len(path))
+	for i, el := range path {
+		parts[i] = strings.ToUpper(fmt.Sprint(el))
+	}
+	return prefix + strings.Join(parts, sep) + "=" + value
+}
+
+func simpleSlice(s []interface{}) bool {
+	for _, el := range s {
+		switch el.(type) {
+		case Params, map[string]interface{}, []interface{}:
+			return false
+		}
+	}
+	return true
+}
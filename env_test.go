@@ -0,0 +1,97 @@
+package whatever
+
+import (
+	"reflect"
+	"testing"
+)
+
+var environ = []string{
+	"APP_NAME=example",
+	"APP_DB__HOST=localhost",
+	"APP_DB__PORT=5432",
+	"APP_DB__RATIO=0.5",
+	"APP_DEBUG=true",
+	"APP_HOSTS=a, b,c",
+	"APP_=ignored",
+	"OTHER_NAME=ignored",
+}
+
+func TestNewFromEnv(t *testing.T) {
+	p := fromEnviron(environ, "APP_", "__")
+	expected := Params{
+		"name":  "example",
+		"db":    Params{"host": "localhost", "port": "5432", "ratio": "0.5"},
+		"debug": "true",
+		"hosts": "a, b,c",
+	}
+	if !reflect.DeepEqual(expected, p) {
+		wrong(t, "NewFromEnv", expected, p)
+	}
+
+	p = fromEnviron(environ, "APP_", "__", SplitLists(""), InferTypes())
+	expected = Params{
+		"name":  "example",
+		"db":    Params{"host": "localhost", "port": 5432, "ratio": 0.5},
+		"debug": true,
+		"hosts": []interface{}{"a", "b", "c"},
+	}
+	if !reflect.DeepEqual(expected, p) {
+		wrong(t, "NewFromEnv", expected, p)
+	}
+
+	p = fromEnviron([]string{"APP_DB=plain", "APP_DB__HOST=localhost"}, "APP_", "__")
+	if got := p.GetP("db").GetString("host"); got != "localhost" {
+		wrong(t, "NewFromEnv", "localhost", got)
+	}
+}
+
+func TestNewFromEnv_nestedKeysWin(t *testing.T) {
+	for _, sep := range []string{"__", ".", "-", "_"} {
+		environ := []string{"APP_DB" + sep + "HOST=localhost", "APP_DB=plain"}
+		p := fromEnviron(environ, "APP_", sep)
+		if got := p.GetP("db").GetString("host"); got != "localhost" {
+			wrong(t, "NewFromEnv with "+sep, "localhost", got)
+		}
+	}
+}
+
+func TestNewFromEnv_floats(t *testing.T) {
+	environ := []string{
+		"APP_A=1.5", "APP_B=-2e3", "APP_C=.5", "APP_D=3.",
+		"APP_E=inf", "APP_F=NaN", "APP_G=Infinity", "APP_H=0x1p-2", "APP_I=1e",
+	}
+	p := fromEnviron(environ, "APP_", "", InferTypes())
+	expected := Params{
+		"a": 1.5, "b": -2e3, "c": 0.5, "d": 3.0,
+		"e": "inf", "f": "NaN", "g": "Infinity", "h": "0x1p-2", "i": "1e",
+	}
+	if !reflect.DeepEqual(expected, p) {
+		wrong(t, "NewFromEnv", expected, p)
+	}
+}
+
+func TestToEnv(t *testing.T) {
+	p := Params{
+		"name":  "example",
+		"db":    Params{"host": "localhost", "port": 5432},
+		"hosts": []interface{}{"a", "b"},
+		"users": []interface{}{Params{"name": "root"}},
+	}
+	expected := []string{
+		"APP_DB__HOST=localhost",
+		"APP_DB__PORT=5432",
+		"APP_HOSTS=a,b",
+		"APP_NAME=example",
+		"APP_USERS__0__NAME=root",
+	}
+	got := p.ToEnv("APP_", "__")
+	if !reflect.DeepEqual(expected, got) {
+		wrong(t, "ToEnv", expected, got)
+	}
+
+	back := fromEnviron(got[:4], "APP_", "__", SplitLists(","), InferTypes())
+	delete(p, "users")
+	if !Equal(p, back) {
+		wrong(t, "ToEnv", p, back)
+	}
+}
@@ -0,0 +1,136 @@
+package whatever
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// EqualOption configures Equal and Differences.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	normalizeNumbers bool
+	nilAsMissing     bool
+	ignoreSliceOrder bool
+	ignore           [][]string
+}
+
+// NormalizeNumbers compares all numeric types by value,
+// so int(1) and float64(1) are equal.
+func NormalizeNumbers() EqualOption {
+	return func(o *equalOptions) {
+		o.normalizeNumbers = true
+	}
+}
+
+// NilAsMissing treats the keys with nil values as missing,
+// so {"a": nil} and {} are equal.
+func NilAsMissing() EqualOption {
+	return func(o *equalOptions) {
+		o.nilAsMissing = true
+	}
+}
+
+// IgnoreSliceOrder compares slices as multisets - they are
+// equal if every element has an equal counterpart.
+func IgnoreSliceOrder() EqualOption {
+	return func(o *equalOptions) {
+		o.ignoreSliceOrder = true
+	}
+}
+
+// IgnorePaths skips the values under the paths in dotted
+// notation. The paths can contain wildcards as with Pick:
+//
+//	whatever.IgnorePaths("updated_at", "items.*.id")
+func IgnorePaths(paths ...string) EqualOption {
+	return func(o *equalOptions) {
+		o.ignore = append(o.ignore, splitPatterns(paths)...)
+	}
+}
+
+// Equal compares two Params structures deeply. Unlike
+// reflect.DeepEqual, nested Params and map[string]interface{}
+// with the same contents are equal and time.Time values are
+// compared with their Equal method. The comparison can be
+// relaxed with options:
+//
+//	whatever.Equal(a, b, whatever.NormalizeNumbers(), whatever.IgnorePaths("id"))
+func Equal(a, b Params, opts ...EqualOption) bool {
+	return len(Differences(a, b, opts...)) == 0
+}
+
+// Differences compares two Params structures as Equal does
+// and returns a readable description of every difference,
+// one per line, suitable for test failures:
+//
+//	name: missing in b
+//	added: missing in a
+//	nested.count: 1 (int) != 1 (float64)
+//
+// The differences are found by the same walk as Compare
+// and are listed in the same order. Returns nil if the
+// structures are equal.
+func Differences(a, b Params, opts ...EqualOption) []string {
+	var o equalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var result []string
+	for _, change := range o.compare(nil, rootMap(a), rootMap(b), nil) {
+		switch {
+		case change.Type == Changed:
+			result = append(result, fmt.Sprintf("%s: %s != %s", pathName(change.Path), describe(change.Old), describe(change.New)))
+		case change.Type == Removed && change.unmatched:
+			result = append(result, fmt.Sprintf("%s: %s has no match in b", change.Path, describe(change.Old)))
+		case change.Type == Removed:
+			result = append(result, fmt.Sprintf("%s: missing in b", change.Path))
+		case change.unmatched:
+			result = append(result, fmt.Sprintf("%s: %s has no match in a", change.Path, describe(change.New)))
+		default:
+			result = append(result, fmt.Sprintf("%s: missing in a", change.Path))
+		}
+	}
+	return result
+}
+
+func (o *equalOptions) equalLeaves(a, b interface{}) bool {
+	if o.normalizeNumbers {
+		if fa, ok := toFloat(a); ok {
+			fb, ok := toFloat(b)
+			return ok && fa == fb
+		}
+	}
+
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func pathName(path Path) string {
+	if len(path) == 0 {
+		return "(root)"
+	}
+	return path.String()
+}
+
+func describe(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+
+	value := fmt.Sprintf("%v", v)
+	if len(value) > 60 {
+		value = strings.TrimSpace(value[:57]) + "..."
+	}
+	return fmt.Sprintf("%s (%T)", value, v)
+}
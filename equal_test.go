@@ -0,0 +1,111 @@
+package whatever
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEqual(t *testing.T) {
+	now := time.Now()
+	a := Params{
+		"one":    1,
+		"nested": Params{"list": []interface{}{"a", map[string]interface{}{"b": 2}}},
+		"time":   now,
+	}
+	b := Params{
+		"one":    1,
+		"nested": map[string]interface{}{"list": []interface{}{"a", Params{"b": 2}}},
+		"time":   now.UTC(),
+	}
+
+	if !Equal(a, b) {
+		wrong(t, "Equal", true, Differences(a, b))
+	}
+
+	if !Equal(Params{}, nil) {
+		wrong(t, "Equal", true, false)
+	}
+}
+
+func TestEqual_options(t *testing.T) {
+	cases := []struct {
+		a, b Params
+		opts []EqualOption
+	}{
+		{Params{"n": 1}, Params{"n": 1.0}, []EqualOption{NormalizeNumbers()}},
+		{Params{"n": int8(1)}, Params{"n": uint64(1)}, []EqualOption{NormalizeNumbers()}},
+		{Params{"n": nil}, Params{}, []EqualOption{NilAsMissing()}},
+		{Params{"l": []interface{}{1, 2, 2}}, Params{"l": []interface{}{2, 1, 2}}, []EqualOption{IgnoreSliceOrder()}},
+		{
+			Params{"id": 1, "items": []interface{}{Params{"id": 1, "x": 1}}},
+			Params{"id": 2, "items": []interface{}{Params{"id": 2, "x": 1}}},
+			[]EqualOption{IgnorePaths("id", "items.*.id")},
+		},
+	}
+
+	for _, c := range cases {
+		if Equal(c.a, c.b) {
+			wrong(t, "Equal", false, true)
+		}
+		if !Equal(c.a, c.b, c.opts...) {
+			wrong(t, "Equal", true, Differences(c.a, c.b, c.opts...))
+		}
+	}
+
+	if Equal(Params{"l": []interface{}{1, 2}}, Params{"l": []interface{}{2, 2}}, IgnoreSliceOrder()) {
+		wrong(t, "Equal", false, true)
+	}
+}
+
+func TestDifferences(t *testing.T) {
+	a := Params{
+		"same":    "x",
+		"changed": 1,
+		"removed": true,
+		"nested":  Params{"list": []interface{}{1, 2, 3}},
+	}
+	b := Params{
+		"same":    "x",
+		"changed": 1.0,
+		"added":   "y",
+		"nested":  Params{"list": []interface{}{1, 5}},
+	}
+
+	expected := []string{
+		"removed: missing in b",
+		"added: missing in a",
+		"changed: 1 (int) != 1 (float64)",
+		"nested.list.1: 2 (int) != 5 (int)",
+		"nested.list.2: missing in b",
+	}
+	got := Differences(a, b)
+	if fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "Differences", expected, got)
+	}
+
+	if got := Differences(a, a); got != nil {
+		wrong(t, "Differences", nil, got)
+	}
+}
+
+func TestDifferences_sameWalkAsCompare(t *testing.T) {
+	a := Params{"n": 1, "l": []interface{}{1, 2}, "m": Params{"x": nil}}
+	b := Params{"n": 1.0, "l": []interface{}{2, 3}, "m": Params{}}
+
+	expected := []string{
+		"l.0: 1 (int) has no match in b",
+		"l.1: 3 (int) has no match in a",
+		"m.x: missing in b",
+		"n: 1 (int) != 1 (float64)",
+	}
+	got := Differences(a, b, IgnoreSliceOrder())
+	if fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "Differences", expected, got)
+	}
+
+	changes := Compare(a, b)
+	if got := Differences(a, b, NormalizeNumbers()); len(got) != len(changes) {
+		wrong(t, "Differences", changes.String(), got)
+	}
+}
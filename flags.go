@@ -0,0 +1,199 @@
+package whatever
+
+import (
+	"flag"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BindFlags registers a flag in fs for every leaf of p, named
+// by its path in dotted notation, e.g. -db.host for
+// {"db": {"host": "localhost"}}. The current values are the
+// defaults of the flags and their types choose the kind of the
+// flags: strings, booleans, all integer and float types,
+// time.Duration and time.Time (in RFC3339 format). Slices of
+// simple values accept comma separated lists and can be
+// repeated. The maps in slices are bound with the indexes in the
+// names, e.g. -servers.0.host. All other values are bound as
+// strings.
+//
+// When fs is parsed, the values of the given flags are written
+// back into p, so the command line overrides the loaded
+// configuration:
+//
+//	whatever.BindFlags(flag.CommandLine, p)
+//	flag.Parse()
+//
+// Like the other flag definitions, BindFlags panics if
+// a flag with the same name is already defined.
+func BindFlags(fs *flag.FlagSet, p Params) {
+	bindFlags(fs, "", p)
+}
+
+func bindFlags(fs *flag.FlagSet, prefix string, m map[string]interface{}) {
+	for _, k := range sortedKeys(m) {
+		name := k
+		if prefix != "" {
+			name = prefix + "." + k
+		}
+
+		switch v := m[k].(type) {
+		case Params, map[string]interface{}:
+			nested, _ := toMap(v)
+			bindFlags(fs, name, nested)
+			continue
+		case []interface{}:
+			if !simpleSlice(v) {
+				for i, el := range v {
+					if nested, ok := toMap(el); ok {
+						bindFlags(fs, fmt.Sprintf("%s.%d", name, i), nested)
+					}
+				}
+				continue
+			}
+		}
+
+		fs.Var(&paramFlag{parent: m, key: k}, name, fmt.Sprintf("sets %s", name))
+	}
+}
+
+// paramFlag is the flag.Value of a single leaf of the Params.
+// It parses the flag to the type of the current value.
+type paramFlag struct {
+	parent map[string]interface{}
+	key    string
+	set    bool
+}
+
+func (f *paramFlag) String() string {
+	if f == nil || f.parent == nil {
+		return ""
+	}
+
+	if v, ok := f.parent[f.key].([]interface{}); ok {
+		parts := make([]string, len(v))
+		for i, el := range v {
+			parts[i] = stringify(el)
+		}
+		return strings.Join(parts, ",")
+	}
+	if t, ok := f.parent[f.key].(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	if f.parent[f.key] == nil {
+		return ""
+	}
+	return stringify(f.parent[f.key])
+}
+
+func (f *paramFlag) Set(s string) error {
+	current := f.parent[f.key]
+	if v, ok := current.([]interface{}); ok {
+		var elem interface{}
+		if len(v) > 0 {
+			elem = v[0]
+		}
+
+		// The first use of the flag replaces
+		// the default, the next ones append.
+		var result []interface{}
+		if f.set {
+			result = v
+		}
+		for _, part := range strings.Split(s, ",") {
+			parsed, err := parseFlagValue(elem, strings.TrimSpace(part))
+			if err != nil {
+				return err
+			}
+			result = append(result, parsed)
+		}
+		f.parent[f.key], f.set = result, true
+		return nil
+	}
+
+	parsed, err := parseFlagValue(current, s)
+	if err != nil {
+		return err
+	}
+	f.parent[f.key], f.set = parsed, true
+	return nil
+}
+
+func (f *paramFlag) Get() interface{} {
+	return f.parent[f.key]
+}
+
+func (f *paramFlag) IsBoolFlag() bool {
+	_, ok := f.parent[f.key].(bool)
+	return ok
+}
+
+// parseFlagValue parses s to the type of like,
+// or returns s if like is of some other type.
+func parseFlagValue(like interface{}, s string) (interface{}, error) {
+	switch like.(type) {
+	case time.Duration:
+		return time.ParseDuration(s)
+	case time.Time:
+		return time.Parse(time.RFC3339, s)
+	case nil:
+		return s, nil
+	}
+
+	value := reflect.ValueOf(like)
+	result := reflect.New(value.Type()).Elem()
+	switch value.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, err
+		}
+		result.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, value.Type().Bits())
+		if err != nil {
+			return nil, err
+		}
+		result.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, value.Type().Bits())
+		if err != nil {
+			return nil, err
+		}
+		result.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, value.Type().Bits())
+		if err != nil {
+			return nil, err
+		}
+		result.SetFloat(f)
+	default:
+		return s, nil
+	}
+	return result.Interface(), nil
+}
+
+// FlagSource returns a Source for a Config with the flags
+// that were set on the command line. The names of the flags
+// are the keys in dotted notation. The values of the flags
+// defined with BindFlags keep their types, all other values
+// are strings. The indexed flags, such as -servers.0.host,
+// override only the given fields of the elements of the slices
+// loaded by the previous sources. The name of the source is
+// "flags".
+func FlagSource(fs *flag.FlagSet) Source {
+	return SourceFunc("flags", func() (Params, error) {
+		p := Params{}
+		fs.Visit(func(f *flag.Flag) {
+			if getter, ok := f.Value.(flag.Getter); ok {
+				store(p, f.Name, copyValue(getter.Get()))
+				return
+			}
+			store(p, f.Name, f.Value.String())
+		})
+		return p, nil
+	})
+}
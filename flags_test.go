@@ -0,0 +1,158 @@
+package whatever
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBindFlags(t *testing.T) {
+	p := Params{
+		"name":    "example",
+		"debug":   false,
+		"timeout": 5 * time.Second,
+		"since":   time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		"db":      Params{"host": "localhost", "port": 5432, "ratio": float32(0.5)},
+		"hosts":   []interface{}{"a"},
+		"ports":   []interface{}{1},
+		"servers": []interface{}{Params{"host": "s1"}},
+		"options": map[string]interface{}{"level": int8(1)},
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	BindFlags(fs, p)
+
+	if f := fs.Lookup("db.port"); f == nil || f.DefValue != "5432" {
+		t.Fatalf("BindFlags did not register db.port with its default")
+	}
+
+	err := fs.Parse([]string{
+		"-debug", "-timeout=1m", "-since=2021-02-03T04:05:06Z",
+		"-db.host=db.local", "-db.port=6543", "-db.ratio=0.25",
+		"-hosts=b,c", "-hosts=d", "-ports=2,3",
+		"-servers.0.host=s2", "-options.level=3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Params{
+		"name":    "example",
+		"debug":   true,
+		"timeout": time.Minute,
+		"since":   time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC),
+		"db":      Params{"host": "db.local", "port": 6543, "ratio": float32(0.25)},
+		"hosts":   []interface{}{"b", "c", "d"},
+		"ports":   []interface{}{2, 3},
+		"servers": []interface{}{Params{"host": "s2"}},
+		"options": map[string]interface{}{"level": int8(3)},
+	}
+	if !Equal(expected, p) {
+		wrong(t, "BindFlags", expected, p)
+	}
+}
+
+func TestBindFlagsErrors(t *testing.T) {
+	for _, arg := range []string{"-port=x", "-level=300", "-timeout=1", "-debug=maybe"} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		BindFlags(fs, Params{"port": 1, "level": int8(1), "timeout": time.Second, "debug": true})
+		if err := fs.Parse([]string{arg}); err == nil {
+			t.Errorf("BindFlags expected an error for %s", arg)
+		}
+	}
+}
+
+func TestFlagSource(t *testing.T) {
+	defaults := Params{"db": Params{"host": "localhost", "port": 5432}}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags(fs, copyValue(defaults).(Params))
+	fs.String("mode", "dev", "")
+	if err := fs.Parse([]string{"-db.port=6543", "-mode=prod"}); err != nil {
+		t.Fatal(err)
+	}
+
+	config := NewConfig(StaticSource("defaults", defaults), FlagSource(fs))
+	p, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Params{"db": Params{"host": "localhost", "port": 6543}, "mode": "prod"}
+	if !Equal(expected, p) {
+		wrong(t, "FlagSource", expected, p)
+	}
+
+	if got, _ := config.Origin("db.port"); got != "flags" {
+		wrong(t, "Config.Origin", "flags", got)
+	}
+}
+
+func TestFlagSource_sliceElements(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	body := `{"servers": [{"host": "a", "port": 1}, {"host": "b", "port": 2}]}`
+	if err := os.WriteFile(file, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config := NewConfig(FileSource(file))
+	template, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	BindFlags(fs, copyValue(template).(Params))
+	if err := fs.Parse([]string{"-servers.0.host=z"}); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := config.Add(FlagSource(fs)).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Params{"servers": []interface{}{
+		map[string]interface{}{"host": "z", "port": 1.0},
+		map[string]interface{}{"host": "b", "port": 2.0},
+	}}
+	if !Equal(expected, p) {
+		wrong(t, "FlagSource", expected, p)
+	}
+
+	origins := map[string]string{
+		"servers.0.host": "flags",
+		"servers.0.port": "file:" + file,
+		"servers.1.host": "file:" + file,
+	}
+	for key, name := range origins {
+		if got, _ := config.Origin(key); got != name {
+			wrong(t, "Config.Origin "+key, name, got)
+		}
+	}
+}
+
+func TestFlagSource_emptyMapReplacesSlice(t *testing.T) {
+	config := NewConfig(
+		StaticSource("defaults", Params{"servers": []interface{}{Params{"host": "a"}}}),
+		StaticSource("override", Params{"servers": Params{}}),
+	)
+
+	p, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Params{"servers": Params{}}
+	if !Equal(expected, p) {
+		wrong(t, "Config.Load", expected, p)
+	}
+	if got, _ := config.Origin("servers"); got != "override" {
+		wrong(t, "Config.Origin", "override", got)
+	}
+}
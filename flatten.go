@@ -0,0 +1,120 @@
+package whatever
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flatten returns a map with the paths of all leaf values in
+// the Params structure joined with sep as keys. Slice elements
+// are addressed by their index. Example with sep ".":
+//
+//	{"db": {"hosts": ["a", "b"], "port": 5432}}
+//
+// will result in:
+//
+//	{"db.hosts.0": "a", "db.hosts.1": "b", "db.port": 5432}
+//
+// Empty objects and slices are kept as leaf values, so
+// Unflatten can restore the structure exactly. Keep in mind
+// that keys containing sep cannot be restored.
+// If sep is empty string, "." is used.
+func (p Params) Flatten(sep string) map[string]interface{} {
+	if sep == "" {
+		sep = "."
+	}
+
+	result := map[string]interface{}{}
+	p.Walk(func(path Path, value interface{}) error {
+		if m, ok := toMap(value); ok && len(m) > 0 {
+			return nil
+		}
+		if s, ok := value.([]interface{}); ok && len(s) > 0 {
+			return nil
+		}
+
+		parts := make([]string, len(path))
+		for i, el := range path {
+			parts[i] = fmt.Sprint(el)
+		}
+		result[strings.Join(parts, sep)] = copyValue(value)
+		return nil
+	})
+	return result
+}
+
+// Unflatten rebuilds a Params structure from the flat map
+// produced by Flatten using the same separator. Objects whose
+// keys are exactly the indexes 0 to n-1 are restored as slices.
+// Returns an error if two keys conflict, for example "a" and "a.b".
+// If sep is empty string, "." is used.
+func Unflatten(flat map[string]interface{}, sep string) (Params, error) {
+	if sep == "" {
+		sep = "."
+	}
+
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := flatNode{}
+	for _, key := range keys {
+		parts := strings.Split(key, sep)
+		node := root
+		for i, part := range parts[:len(parts)-1] {
+			child, found := node[part]
+			if !found {
+				child = flatNode{}
+				node[part] = child
+			}
+			nested, ok := child.(flatNode)
+			if !ok {
+				return nil, fmt.Errorf("the key %s conflicts with %s", key, strings.Join(parts[:i+1], sep))
+			}
+			node = nested
+		}
+
+		last := parts[len(parts)-1]
+		if _, found := node[last]; found {
+			return nil, fmt.Errorf("the key %s conflicts with a nested key", key)
+		}
+		node[last] = copyValue(flat[key])
+	}
+
+	result := Params{}
+	for k, v := range root {
+		if nested, ok := v.(flatNode); ok {
+			v = nested.restore()
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+// flatNode is an object created by Unflatten. It is
+// a separate type so it is not confused with the values.
+type flatNode map[string]interface{}
+
+// restore converts the node to a slice if its keys are exactly
+// the indexes 0 to n-1 or to Params otherwise.
+func (n flatNode) restore() interface{} {
+	for k, v := range n {
+		if nested, ok := v.(flatNode); ok {
+			n[k] = nested.restore()
+		}
+	}
+
+	slice := make([]interface{}, len(n))
+	for k, v := range n {
+		index, err := strconv.Atoi(k)
+		if err != nil || index < 0 || index >= len(n) || strconv.Itoa(index) != k {
+			return Params(n)
+		}
+		slice[index] = v
+	}
+	return slice
+}
@@ -0,0 +1,82 @@
+package whatever
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParams_Flatten(t *testing.T) {
+	p := Params{
+		"db": map[string]interface{}{
+			"hosts": []interface{}{"a", Params{"name": "b"}},
+			"port":  5432,
+		},
+		"empty":     Params{},
+		"emptyList": []interface{}{},
+		"top":       true,
+	}
+
+	expected := map[string]interface{}{
+		"db_hosts_0":      "a",
+		"db_hosts_1_name": "b",
+		"db_port":         5432,
+		"empty":           Params{},
+		"emptyList":       []interface{}{},
+		"top":             true,
+	}
+
+	got := p.Flatten("_")
+	if !reflect.DeepEqual(expected, got) {
+		wrong(t, "Flatten", expected, got)
+	}
+
+	if _, ok := p.Flatten("")["db.port"]; !ok {
+		wrong(t, "Flatten", "db.port", p.Flatten(""))
+	}
+
+	restored, err := Unflatten(got, "_")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !jsonEqual(p, restored) {
+		wrong(t, "Unflatten", p, restored)
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	got, err := Unflatten(map[string]interface{}{
+		"a.b":   1,
+		"a.c.0": "x",
+		"a.c.1": "y",
+		"d.1":   "not a slice",
+		"e.0":   "slice",
+		"0":     "top-level",
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Params{
+		"a": Params{"b": 1, "c": []interface{}{"x", "y"}},
+		"d": Params{"1": "not a slice"},
+		"e": []interface{}{"slice"},
+		"0": "top-level",
+	}
+	if !reflect.DeepEqual(expected, got) {
+		wrong(t, "Unflatten", expected, got)
+	}
+}
+
+func TestUnflatten_conflict(t *testing.T) {
+	conflicts := []map[string]interface{}{
+		{"a": 1, "a.b": 2},
+		{"a.b.c": 1, "a.b": 2},
+	}
+
+	for _, flat := range conflicts {
+		if _, err := Unflatten(flat, "."); err == nil {
+			wrong(t, "Unflatten", "an error", nil)
+		}
+	}
+}
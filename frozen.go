@@ -0,0 +1,218 @@
+package whatever
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Frozen is an immutable version of Params. It has the same
+// getters as Params, but the methods that would normally modify
+// the structure return a new Frozen instead. The new version shares
+// all untouched nested objects with the old one, so creating it is
+// cheap no matter how big the structure is.
+//
+// Because a Frozen is never modified after it is created it can be
+// read from many goroutines at the same time without any locking.
+//
+// The getters that return reference values (GetI, GetSlice) return
+// deep copies, so the callers cannot change the frozen data.
+type Frozen struct {
+	params Params
+}
+
+// Freeze returns a Frozen snapshot of the Params.
+// The Params is deep copied, so later changes to it
+// will not be visible in the snapshot.
+func (p Params) Freeze() Frozen {
+	params, _ := copyValue(p).(Params)
+	return Frozen{params: params}
+}
+
+// Thaw returns a deep copy of the frozen data as a
+// regular Params that can be modified.
+func (f Frozen) Thaw() Params {
+	if f.params == nil {
+		return Params{}
+	}
+	return copyValue(f.params).(Params)
+}
+
+// Set returns a new Frozen with the value under the dotted
+// path set to value. The missing intermediate objects are created.
+// If an existing value on the path is not an object, e.g. a string
+// or a slice, nothing is replaced and the same Frozen is returned.
+func (f Frozen) Set(path string, value interface{}) Frozen {
+	if !canStore(f.params, path) {
+		return f
+	}
+	return Frozen{params: setIn(f.params, path, copyValue(value))}
+}
+
+// Delete returns a new Frozen without the value under the
+// dotted path. If the path is missing the same Frozen is returned.
+func (f Frozen) Delete(path string) Frozen {
+	if _, ok := lookup(f.params, path); !ok {
+		return f
+	}
+	return Frozen{params: deleteIn(f.params, path)}
+}
+
+// Merge returns a new Frozen with all top-level fields
+// from set added to it.
+func (f Frozen) Merge(set map[string]interface{}) Frozen {
+	result := shallowCopy(f.params)
+	for k, v := range set {
+		result[k] = copyValue(v)
+	}
+	return Frozen{params: result}
+}
+
+// Defaults returns a new Frozen with the top-level fields
+// from set that are missing in f added to it.
+func (f Frozen) Defaults(set map[string]interface{}) Frozen {
+	result := shallowCopy(f.params)
+	for k, v := range set {
+		if _, ok := result[k]; !ok {
+			result[k] = copyValue(v)
+		}
+	}
+	return Frozen{params: result}
+}
+
+// Empty checks if the structure is empty.
+func (f Frozen) Empty() bool {
+	return f.params.Empty()
+}
+
+// Required works as Params.Required.
+func (f Frozen) Required(keys ...string) error {
+	return f.params.Required(keys...)
+}
+
+// Keys returns the top-level keys.
+func (f Frozen) Keys() []string {
+	return f.params.Keys()
+}
+
+// NestedKeys returns all keys in dotted notation.
+func (f Frozen) NestedKeys() []string {
+	return f.params.NestedKeys()
+}
+
+// URLValues works as Params.URLValues.
+func (f Frozen) URLValues(prefix, suffix string) url.Values {
+	return f.params.URLValues(prefix, suffix)
+}
+
+// GetP returns the nested object with the specified key as Frozen.
+// The nested object is shared, not copied.
+func (f Frozen) GetP(key string) Frozen {
+	return Frozen{params: f.params.GetP(key)}
+}
+
+// GetI returns a deep copy of the value with the specified key.
+func (f Frozen) GetI(key string) interface{} {
+	return copyValue(f.params.GetI(key))
+}
+
+// Get works as Params.Get.
+func (f Frozen) Get(key string) string {
+	return f.params.Get(key)
+}
+
+// GetString works as Params.GetString.
+func (f Frozen) GetString(key string) string {
+	return f.params.GetString(key)
+}
+
+// GetInt works as Params.GetInt.
+func (f Frozen) GetInt(key string) int {
+	return f.params.GetInt(key)
+}
+
+// GetInt8 works as Params.GetInt8.
+func (f Frozen) GetInt8(key string) int8 {
+	return f.params.GetInt8(key)
+}
+
+// GetInt64 works as Params.GetInt64.
+func (f Frozen) GetInt64(key string) int64 {
+	return f.params.GetInt64(key)
+}
+
+// GetFloat works as Params.GetFloat.
+func (f Frozen) GetFloat(key string) float32 {
+	return f.params.GetFloat(key)
+}
+
+// GetFloat32 works as Params.GetFloat32.
+func (f Frozen) GetFloat32(key string) float32 {
+	return f.params.GetFloat32(key)
+}
+
+// GetFloat64 works as Params.GetFloat64.
+func (f Frozen) GetFloat64(key string) float64 {
+	return f.params.GetFloat64(key)
+}
+
+// GetTime works as Params.GetTime.
+func (f Frozen) GetTime(key string) time.Time {
+	return f.params.GetTime(key)
+}
+
+// GetSlice returns a deep copy of the slice with the specified key.
+func (f Frozen) GetSlice(key string) []interface{} {
+	if slice := f.params.GetSlice(key); slice != nil {
+		return copyValue(slice).([]interface{})
+	}
+	return nil
+}
+
+// GetSliceStrings works as Params.GetSliceStrings.
+func (f Frozen) GetSliceStrings(key string) []string {
+	return f.params.GetSliceStrings(key)
+}
+
+// GetSliceInts works as Params.GetSliceInts.
+func (f Frozen) GetSliceInts(key string) []int {
+	return f.params.GetSliceInts(key)
+}
+
+// shallowCopy copies only the top level of set into a new Params.
+func shallowCopy(set map[string]interface{}) Params {
+	result := make(Params, len(set)+1)
+	for k, v := range set {
+		result[k] = v
+	}
+	return result
+}
+
+// setIn returns a copy of set with value stored under the
+// dotted path. Only the objects on the path are copied.
+func setIn(set map[string]interface{}, path string, value interface{}) Params {
+	result := shallowCopy(set)
+	if index := strings.Index(path, "."); index != -1 {
+		nested, _ := toMap(result[path[:index]])
+		result[path[:index]] = setIn(nested, path[index+1:], value)
+		return result
+	}
+
+	result[path] = value
+	return result
+}
+
+// deleteIn returns a copy of set without the dotted path.
+// Only the objects on the path are copied.
+func deleteIn(set map[string]interface{}, path string) Params {
+	result := shallowCopy(set)
+	if index := strings.Index(path, "."); index != -1 {
+		if nested, ok := toMap(result[path[:index]]); ok {
+			result[path[:index]] = deleteIn(nested, path[index+1:])
+		}
+		return result
+	}
+
+	delete(result, path)
+	return result
+}
@@ -0,0 +1,129 @@
+package whatever
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestParams_Freeze(t *testing.T) {
+	p := parse(body)
+	f := p.Freeze()
+
+	p.Add("string", "changed")
+	p.GetP("nestedParams").Add("one", 100)
+
+	if got := f.Get("string"); got != "test" {
+		wrong(t, "Freeze", "test", got)
+	}
+
+	if got := f.GetP("nestedParams").GetInt("one"); got != 1 {
+		wrong(t, "Freeze", 1, got)
+	}
+
+	f.GetSlice("arrayInts")[0] = 100
+	if got := f.GetSliceInts("arrayInts"); !equalSlicesInts([]int{1, 2, 3, 4}, got) {
+		wrong(t, "Frozen.GetSlice", []int{1, 2, 3, 4}, got)
+	}
+
+	if err := f.Required("nestedParams.params2.three"); err != nil {
+		wrong(t, "Frozen.Required", nil, err)
+	}
+}
+
+func TestFrozen_Set(t *testing.T) {
+	f := Params{
+		"one":    1,
+		"nested": Params{"two": 2},
+		"other":  Params{"three": 3},
+	}.Freeze()
+
+	f2 := f.Set("nested.two", 20).Set("new.key", "value")
+
+	if got := f.GetP("nested").GetInt("two"); got != 2 {
+		wrong(t, "Frozen.Set", 2, got)
+	}
+
+	if got := f2.GetP("nested").GetInt("two"); got != 20 {
+		wrong(t, "Frozen.Set", 20, got)
+	}
+
+	if got := f2.GetP("new").Get("key"); got != "value" {
+		wrong(t, "Frozen.Set", "value", got)
+	}
+
+	// Untouched nested objects are shared.
+	if reflect.ValueOf(f.params["other"]).Pointer() != reflect.ValueOf(f2.params["other"]).Pointer() {
+		wrong(t, "Frozen.Set", "shared nested object", "a copy")
+	}
+}
+
+func TestFrozen_SetIntermediates(t *testing.T) {
+	p := Params{"name": "x", "list": []interface{}{1, 2}}
+	f := p.Freeze()
+
+	for _, path := range []string{"list.0", "name.first"} {
+		if got := f.Set(path, 9).Thaw(); !reflect.DeepEqual(p, got) {
+			wrong(t, "Frozen.Set "+path, p, got)
+		}
+	}
+}
+
+func TestFrozen_Delete(t *testing.T) {
+	f := Params{"one": 1, "nested": Params{"two": 2, "three": 3}}.Freeze()
+	f2 := f.Delete("nested.two").Delete("one")
+
+	if err := f.Required("one", "nested.two"); err != nil {
+		wrong(t, "Frozen.Delete", nil, err)
+	}
+
+	if err := f2.Required("one"); err == nil {
+		wrong(t, "Frozen.Delete", "the parameter one is required", nil)
+	}
+
+	if err := f2.Required("nested.two"); err == nil {
+		wrong(t, "Frozen.Delete", "the parameter nested.two is required", nil)
+	}
+
+	if got := f2.GetP("nested").GetInt("three"); got != 3 {
+		wrong(t, "Frozen.Delete", 3, got)
+	}
+}
+
+func TestFrozen_MergeDefaults(t *testing.T) {
+	f := Params{"one": 1}.Freeze()
+	merged := f.Merge(Params{"one": 10, "two": 2})
+	defaulted := f.Defaults(Params{"one": 10, "two": 2})
+
+	if got := f.GetInt("one"); got != 1 {
+		wrong(t, "Frozen.Merge", 1, got)
+	}
+
+	if got := merged.GetInt("one"); got != 10 {
+		wrong(t, "Frozen.Merge", 10, got)
+	}
+
+	if got := defaulted.GetInt("one") + defaulted.GetInt("two"); got != 3 {
+		wrong(t, "Frozen.Defaults", 3, got)
+	}
+}
+
+func TestFrozen_concurrent(t *testing.T) {
+	f := parse(body).Freeze()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			f.Set("nestedParams.one", i).Get("nestedParams")
+			f.GetP("nestedParams").NestedKeys()
+			f.Thaw().Add("int", i)
+		}(i)
+	}
+	wg.Wait()
+
+	if got := f.GetInt("int"); got != -10 {
+		wrong(t, "Frozen", -10, got)
+	}
+}
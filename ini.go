@@ -0,0 +1,165 @@
+package whatever
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NewFromINI receives a slice of bytes that should be an INI
+// file and returns Params structure for it and an error if there
+// was one while parsing. The keys before the first section are
+// top-level keys and every [section] becomes nested Params.
+// Dots in the section names and in the keys are treated as
+// nesting, so
+//
+//	[server.tls]
+//	cert.path = /etc/cert.pem
+//
+// can be validated with Required("server.tls.cert.path").
+//
+// Both "=" and ":" separate the keys from the values. The lines
+// starting with ";" or "#" are comments, as are the trailing
+// " ;" and " #" parts of unquoted values. Values in double
+// quotes can contain escape sequences. All values are strings.
+// A key can't be both a value and a section, e.g. "a = 1"
+// followed by [a] or "a.b = 2" is an error.
+func NewFromINI(iniBody []byte) (Params, error) {
+	p := Params{}
+	section := ""
+	scanner := bufio.NewScanner(bytes.NewReader(iniBody))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("line %d: the section name is not closed", lineNo)
+			}
+			section = strings.TrimSpace(line[1:end])
+			if section == "" {
+				return nil, fmt.Errorf("line %d: the section name is empty", lineNo)
+			}
+			if err := storeINI(p, section, Params{}, "["+section+"]"); err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			continue
+		}
+
+		separator := strings.IndexAny(line, "=:")
+		if separator <= 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+
+		key := strings.TrimSpace(line[:separator])
+		value, err := iniValue(strings.TrimSpace(line[separator+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+
+		if section != "" {
+			key = section + "." + key
+		}
+		if err := storeINI(p, key, value, key); err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// storeINI stores value under the dotted key unless it would
+// replace a value with a section or a section with a value.
+// name is the key or the [section] that is being stored.
+func storeINI(p Params, key string, value interface{}, name string) error {
+	for i := range key {
+		if key[i] != '.' {
+			continue
+		}
+		if v, ok := lookup(p, key[:i]); ok {
+			if _, isMap := toMap(v); !isMap {
+				return fmt.Errorf("key %s conflicts with %s", key[:i], name)
+			}
+		}
+	}
+
+	existing, ok := lookup(p, key)
+	if !ok {
+		store(p, key, value)
+		return nil
+	}
+
+	existingMap, isSection := toMap(existing)
+	if _, ok := toMap(value); ok {
+		if !isSection {
+			return fmt.Errorf("key %s conflicts with %s", key, name)
+		}
+		return nil
+	}
+	if isSection {
+		return fmt.Errorf("key %s conflicts with %s", name, iniFirstKey(key, existingMap))
+	}
+	store(p, key, value)
+	return nil
+}
+
+// iniFirstKey returns the first value nested under the
+// dotted key in m, or the key as section if there is none.
+func iniFirstKey(key string, m map[string]interface{}) string {
+	keys := sortedKeys(m)
+	if len(keys) == 0 {
+		return "[" + key + "]"
+	}
+	if nested, ok := toMap(m[keys[0]]); ok {
+		return iniFirstKey(key+"."+keys[0], nested)
+	}
+	return key + "." + keys[0]
+}
+
+func iniValue(raw string) (string, error) {
+	if strings.HasPrefix(raw, `"`) {
+		end := closingQuote(raw)
+		if end == -1 {
+			return "", fmt.Errorf("the quoted value is not closed")
+		}
+		return strconv.Unquote(raw[:end+1])
+	}
+
+	if strings.HasPrefix(raw, "'") {
+		end := strings.IndexByte(raw[1:], '\'')
+		if end == -1 {
+			return "", fmt.Errorf("the quoted value is not closed")
+		}
+		return raw[1 : end+1], nil
+	}
+
+	for _, comment := range []string{" ;", " #", "\t;", "\t#"} {
+		if index := strings.Index(raw, comment); index != -1 {
+			raw = raw[:index]
+		}
+	}
+	return strings.TrimSpace(raw), nil
+}
+
+// closingQuote returns the index of the double quote that
+// closes the one at the beginning of s, skipping the escaped
+// quotes, or -1 if there is none.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
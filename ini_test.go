@@ -0,0 +1,68 @@
+package whatever
+
+import "testing"
+
+var iniBody = []byte(`
+; global settings
+name = example
+
+[server]
+host = localhost ; inline comment
+port: 8080
+
+[server.tls]
+cert.path = /etc/cert.pem
+motd = "hello\n# world"
+`)
+
+func TestNewFromINI(t *testing.T) {
+	p, err := NewFromINI(iniBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Required("name", "server.host", "server.port", "server.tls.cert.path"); err != nil {
+		wrong(t, "Required", nil, err)
+	}
+
+	if got := p.GetP("server").GetString("host"); got != "localhost" {
+		wrong(t, "NewFromINI", "localhost", got)
+	}
+
+	if got := p.GetP("server").GetInt("port"); got != 8080 {
+		wrong(t, "NewFromINI", 8080, got)
+	}
+
+	if got := p.GetP("server").GetP("tls").GetString("motd"); got != "hello\n# world" {
+		wrong(t, "NewFromINI", "hello\n# world", got)
+	}
+}
+
+func TestNewFromINIErrors(t *testing.T) {
+	for _, body := range []string{"[server", "[]", "novalue", `key = "open`} {
+		if _, err := NewFromINI([]byte(body)); err == nil {
+			t.Errorf("NewFromINI(%q) expected an error", body)
+		}
+	}
+}
+
+func TestNewFromINIConflicts(t *testing.T) {
+	cases := map[string]string{
+		"a = 1\n[a]\n":               "line 2: key a conflicts with [a]",
+		"a = 1\na.b = 2\n":           "line 2: key a conflicts with a.b",
+		"a = 1\n[a.b]\n":             "line 2: key a conflicts with [a.b]",
+		"[a]\nb = 1\n[x]\nb.c = 2\n": "",
+		"a.b.c = 1\na.b = 2\n":       "line 2: key a.b conflicts with a.b.c",
+		"[a.b]\n[a]\nb = 1\n":        "line 3: key a.b conflicts with [a.b]",
+		"[a]\nb = 1\n[a]\nb = 2\n":   "",
+	}
+	for body, expected := range cases {
+		_, err := NewFromINI([]byte(body))
+		if expected == "" && err != nil {
+			t.Errorf("NewFromINI(%q) returned %v", body, err)
+		}
+		if expected != "" && (err == nil || err.Error() != expected) {
+			t.Errorf("NewFromINI(%q) expected %q, got %v", body, expected, err)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package whatever
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrNotFound is returned by a Resolver for a
+// name that it doesn't know. The references to
+// such names use their fallback values if any.
+var ErrNotFound = errors.New("not found")
+
+// Resolver returns the value for the name in the references
+// with its prefix, e.g. "HOME" for ${env:HOME}.
+type Resolver func(name string) (string, error)
+
+// EnvResolver resolves the names as environment variables.
+// It is registered for the "env" prefix by default.
+func EnvResolver(name string) (string, error) {
+	if v, ok := os.LookupEnv(name); ok {
+		return v, nil
+	}
+	return "", ErrNotFound
+}
+
+// FileResolver resolves the names as paths of files and returns
+// their contents without the trailing newline, e.g. for
+// ${file:/run/secrets/db_password}. It is not registered by default:
+//
+//	p.Interpolate(whatever.WithResolver("file", whatever.FileResolver))
+func FileResolver(name string) (string, error) {
+	body, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(body), "\r\n"), nil
+}
+
+// InterpolateOption configures Interpolate.
+type InterpolateOption func(*interpolateOptions)
+
+type interpolateOptions struct {
+	resolvers map[string]Resolver
+}
+
+// WithResolver registers r for the references with the
+// given prefix, e.g. "vault" for ${vault:db/password}.
+// It replaces the default resolver with the same prefix.
+func WithResolver(prefix string, r Resolver) InterpolateOption {
+	return func(o *interpolateOptions) {
+		o.resolvers[prefix] = r
+	}
+}
+
+// ReferenceError is a reference that could not be resolved.
+// Path is the dotted key of the value with the reference.
+type ReferenceError struct {
+	Path      string
+	Reference string
+	Err       error
+}
+
+func (e *ReferenceError) Error() string {
+	return fmt.Sprintf("%s: %s: %v", e.Path, e.Reference, e.Err)
+}
+
+func (e *ReferenceError) Unwrap() error {
+	return e.Err
+}
+
+// ReferenceErrors is the list of all unresolved
+// references returned by Interpolate.
+type ReferenceErrors []*ReferenceError
+
+func (e ReferenceErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = err.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Interpolate resolves the references in all string values of the
+// Params structure:
+//
+//	${db.host}          the value under another key in dotted notation
+//	${env:HOME}         the value of a resolver, env is registered by default
+//	${db.port:-5432}    the fallback if the value is missing or empty
+//	$${literal}         an escaped reference, becomes ${literal}
+//
+// A value that is a single reference to another key keeps the type
+// of the referenced value, otherwise the references are replaced
+// by their string form. The fallbacks can contain references too.
+//
+// All unresolved references and reference cycles are returned as
+// ReferenceErrors with the paths of the values that contain them.
+// If there is an error, the Params structure is not modified.
+func (p Params) Interpolate(opts ...InterpolateOption) error {
+	o := interpolateOptions{resolvers: map[string]Resolver{"env": EnvResolver}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	in := &interpolator{
+		root:    copyValue(p).(Params),
+		options: o,
+		state:   map[string]int{},
+		seen:    map[string]bool{},
+	}
+	in.root.Walk(func(path Path, value interface{}) error {
+		if _, ok := value.(string); ok {
+			in.resolve(path.String())
+		}
+		return nil
+	})
+
+	if len(in.errs) > 0 {
+		return in.errs
+	}
+
+	for k := range p {
+		delete(p, k)
+	}
+	for k, v := range in.root {
+		p[k] = v
+	}
+	return nil
+}
+
+const (
+	unresolved = iota
+	resolved
+	failed
+)
+
+type interpolator struct {
+	root    Params
+	options interpolateOptions
+	state   map[string]int
+	stack   []string
+	errs    ReferenceErrors
+	seen    map[string]bool
+}
+
+// errUnresolved is returned by resolve for the values with
+// unresolved references. They are reported where they occur,
+// so the values that refer to them fail without a report.
+var errUnresolved = errors.New("unresolved references")
+
+// resolve resolves all references in the value under the dotted
+// key, including the values nested in it, and returns the result.
+func (in *interpolator) resolve(key string) (interface{}, error) {
+	pointer := DottedToPointer(key)
+	value, err := in.root.Pointer(pointer)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	switch in.state[key] {
+	case resolved:
+		return value, nil
+	case failed:
+		return nil, errUnresolved
+	}
+
+	in.stack = append(in.stack, key)
+	defer func() {
+		in.stack = in.stack[:len(in.stack)-1]
+	}()
+
+	ok := true
+	switch val := value.(type) {
+	case string:
+		var result interface{}
+		if result, ok = in.expand(key, val); ok {
+			in.root.SetPointer(pointer, result)
+		}
+	case Params, map[string]interface{}:
+		m, _ := toMap(val)
+		for _, k := range sortedKeys(m) {
+			if _, err := in.resolve(key + "." + k); err != nil {
+				ok = false
+			}
+		}
+	case []interface{}:
+		for i := range val {
+			if _, err := in.resolve(fmt.Sprintf("%s.%d", key, i)); err != nil {
+				ok = false
+			}
+		}
+	}
+
+	if !ok {
+		in.state[key] = failed
+		return nil, errUnresolved
+	}
+
+	in.state[key] = resolved
+	value, _ = in.root.Pointer(pointer)
+	return value, nil
+}
+
+// expand replaces the references in s, the value under the
+// dotted key. It returns false if a reference is unresolved.
+func (in *interpolator) expand(key, s string) (interface{}, bool) {
+	if !strings.Contains(s, "${") {
+		return s, true
+	}
+
+	var buf strings.Builder
+	ok := true
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "$${") {
+			buf.WriteString("${")
+			i += 2
+			continue
+		}
+
+		if !strings.HasPrefix(s[i:], "${") {
+			buf.WriteByte(s[i])
+			continue
+		}
+
+		end := matchingBrace(s, i+1)
+		if end == -1 {
+			in.fail(key, s[i:], errors.New("the reference is not closed"))
+			return nil, false
+		}
+
+		reference := s[i : end+1]
+		value, resolvedOK := in.reference(key, reference)
+		if !resolvedOK {
+			ok = false
+		} else if reference == s {
+			// A single reference keeps the type of the value.
+			return value, true
+		} else {
+			buf.WriteString(stringify(value))
+		}
+		i = end
+	}
+
+	if !ok {
+		return nil, false
+	}
+	return buf.String(), true
+}
+
+// reference resolves a single reference in the
+// form ${name}, ${prefix:name} or ${name:-fallback}.
+func (in *interpolator) reference(key, reference string) (interface{}, bool) {
+	expr := reference[2 : len(reference)-1]
+	name, fallback, hasFallback := expr, "", false
+	if index := strings.Index(expr, ":-"); index != -1 {
+		name, fallback, hasFallback = expr[:index], expr[index+2:], true
+	}
+
+	value, err := in.lookup(name)
+	switch {
+	case err == nil && (value != "" || !hasFallback):
+		return value, true
+	case hasFallback && (err == nil || errors.Is(err, ErrNotFound)):
+		return in.expand(key, fallback)
+	case err == errUnresolved:
+		return nil, false
+	}
+
+	in.fail(key, reference, err)
+	return nil, false
+}
+
+func (in *interpolator) lookup(name string) (interface{}, error) {
+	if index := strings.Index(name, ":"); index != -1 {
+		if resolver, ok := in.options.resolvers[name[:index]]; ok {
+			return resolver(name[index+1:])
+		}
+	}
+
+	// A reference to a value that is being resolved,
+	// or to one of its parents, is a cycle.
+	for i, k := range in.stack {
+		if k == name || strings.HasPrefix(k, name+".") {
+			cycle := append(append([]string(nil), in.stack[i:]...), name)
+			return nil, fmt.Errorf("reference cycle %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	value, err := in.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return copyValue(value), nil
+}
+
+func (in *interpolator) fail(key, reference string, err error) {
+	id := key + "\x00" + reference
+	if in.seen[id] {
+		return
+	}
+	in.seen[id] = true
+	in.errs = append(in.errs, &ReferenceError{Path: key, Reference: reference, Err: err})
+}
+
+// matchingBrace returns the index of the brace that closes
+// the one at start in s, counting the nested references.
+func matchingBrace(s string, start int) int {
+	depth := 0
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,114 @@
+package whatever
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInterpolate(t *testing.T) {
+	os.Setenv("WHATEVER_INTERPOLATE_TEST", "/home/test")
+	defer os.Unsetenv("WHATEVER_INTERPOLATE_TEST")
+
+	secret := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secret, []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := Params{
+		"db": Params{
+			"host":     "localhost",
+			"port":     5432,
+			"url":      "postgres://${db.host}:${db.port}/${name}",
+			"password": "${file:" + secret + "}",
+		},
+		"name":     "${app.name:-example}",
+		"home":     "${env:WHATEVER_INTERPOLATE_TEST}/data",
+		"port":     "${db.port}",
+		"timeout":  "${env:WHATEVER_MISSING_VARIABLE:-${default_timeout}}",
+		"literal":  "$${db.host}",
+		"copy":     "${db}",
+		"servers":  []interface{}{Params{"host": "${servers.1.host}-replica"}, Params{"host": "${db.host}"}},
+		"empty":    "",
+		"fallback": "${empty:-used}",
+
+		"default_timeout": "30s",
+	}
+
+	resolver := func(name string) (string, error) {
+		if name == "db/password" {
+			return "vault-secret", nil
+		}
+		return "", ErrNotFound
+	}
+	p["vault"] = "${vault:db/password}"
+
+	if err := p.Interpolate(WithResolver("file", FileResolver), WithResolver("vault", resolver)); err != nil {
+		t.Fatal(err)
+	}
+
+	db := Params{
+		"host":     "localhost",
+		"port":     5432,
+		"url":      "postgres://localhost:5432/example",
+		"password": "s3cr3t",
+	}
+	expected := Params{
+		"db":              db,
+		"name":            "example",
+		"home":            "/home/test/data",
+		"port":            5432,
+		"timeout":         "30s",
+		"literal":         "${db.host}",
+		"copy":            db,
+		"servers":         []interface{}{Params{"host": "localhost-replica"}, Params{"host": "localhost"}},
+		"empty":           "",
+		"fallback":        "used",
+		"default_timeout": "30s",
+		"vault":           "vault-secret",
+	}
+	if !Equal(expected, p) {
+		wrong(t, "Interpolate", expected, p)
+	}
+}
+
+func TestInterpolateErrors(t *testing.T) {
+	p := Params{
+		"a":       "${b}",
+		"b":       "${a}",
+		"nested":  Params{"self": "x ${nested}"},
+		"missing": "${nothing} and ${env:WHATEVER_MISSING_VARIABLE}",
+		"open":    "${unclosed",
+		"unknown": "${other:value}",
+		"ok":      "${c}",
+		"c":       "value",
+	}
+
+	err := p.Interpolate()
+	var errs ReferenceErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Interpolate expected ReferenceErrors, got %v", err)
+	}
+
+	expected := []string{
+		"b: ${a}: reference cycle a -> b -> a",
+		"missing: ${nothing}: not found",
+		"missing: ${env:WHATEVER_MISSING_VARIABLE}: not found",
+		"nested.self: ${nested}: reference cycle nested.self -> nested",
+		"open: ${unclosed: the reference is not closed",
+		"unknown: ${other:value}: not found",
+	}
+	if err.Error() != strings.Join(expected, "\n") {
+		wrong(t, "Interpolate", strings.Join(expected, "\n"), err.Error())
+	}
+
+	if !errors.Is(errs[1], ErrNotFound) {
+		t.Error("ReferenceError should unwrap to ErrNotFound")
+	}
+
+	if p.GetString("ok") != "${c}" {
+		t.Error("Interpolate modified the Params on error")
+	}
+}
@@ -0,0 +1,89 @@
+//go:build go1.23
+
+package whatever
+
+import (
+	"iter"
+	"strconv"
+)
+
+// All returns an iterator over the top-level keys and values
+// of the Params structure in map iteration order.
+//
+//	for key, value := range p.All() {
+//		...
+//	}
+func (p Params) All() iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		for k, v := range p {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the top-level keys
+// and values of the Params structure sorted by key.
+func (p Params) Sorted() iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		for _, k := range sortedKeys(p) {
+			if !yield(k, p[k]) {
+				return
+			}
+		}
+	}
+}
+
+// Leaves returns an iterator over the leaf values of
+// the Params structure and their keys in dotted notation.
+// Slices are leaves as well. The keys are in lexicographic
+// order as with NestedKeysWith.
+func (p Params) Leaves() iter.Seq2[string, interface{}] {
+	return func(yield func(string, interface{}) bool) {
+		p.RangeKeys(KeyOptions{Order: Lexicographic, LeavesOnly: true}, yield)
+	}
+}
+
+// Slice returns an iterator over the indexes and elements of
+// the slice with the provided key. If there is no value with
+// that key or the value is not a slice, the iterator is empty.
+func (p Params) Slice(key string) iter.Seq2[int, interface{}] {
+	return func(yield func(int, interface{}) bool) {
+		for i, v := range p.GetSlice(key) {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+// SliceStrings works as Slice, but yields only the elements
+// that can be casted to string, as GetSliceStrings does.
+// The indexes are the positions in the original slice.
+func (p Params) SliceStrings(key string) iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i, v := range p.GetSlice(key) {
+			if vs, ok := v.(string); ok {
+				if !yield(i, vs) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// SliceInts works as Slice, but yields only the elements
+// that can be parsed to int, as GetSliceInts does.
+// The indexes are the positions in the original slice.
+func (p Params) SliceInts(key string) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for i, v := range p.GetSlice(key) {
+			if intVal, err := strconv.ParseInt(stringify(v), 0, 0); err == nil {
+				if !yield(i, int(intVal)) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+//go:build go1.23
+
+package whatever
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParams_All(t *testing.T) {
+	params := parse(body)
+	count := 0
+	for key, value := range params.All() {
+		if stringify(params[key]) != stringify(value) {
+			wrong(t, "All", params[key], value)
+		}
+		count++
+	}
+
+	if count != len(params) {
+		wrong(t, "All", len(params), count)
+	}
+}
+
+func TestParams_Sorted(t *testing.T) {
+	params := Params{"b": 2, "c": 3, "a": 1}
+	var got []string
+	for key, value := range params.Sorted() {
+		got = append(got, fmt.Sprintf("%s=%v", key, value))
+		if key == "b" {
+			break
+		}
+	}
+
+	expected := []string{"a=1", "b=2"}
+	if fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "Sorted", expected, got)
+	}
+}
+
+func TestParams_Leaves(t *testing.T) {
+	params := parse(body)
+	var got []string
+	for key := range params.Leaves() {
+		got = append(got, key)
+	}
+
+	expected := []string{
+		"arrayInts", "arrayStrings", "float32", "float64",
+		"incorectTime", "int", "int64", "int8",
+		"nestedParams.one", "nestedParams.params2.three", "nestedParams.two",
+		"string", "time",
+	}
+	if fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "Leaves", expected, got)
+	}
+}
+
+func TestParams_Slice(t *testing.T) {
+	params := Params{"mixed": []interface{}{"one", 2, "three", "4"}}
+
+	var all, strings, ints []string
+	for i, v := range params.Slice("mixed") {
+		all = append(all, fmt.Sprintf("%d %v", i, v))
+	}
+	for i, v := range params.SliceStrings("mixed") {
+		strings = append(strings, fmt.Sprintf("%d %v", i, v))
+	}
+	for i, v := range params.SliceInts("mixed") {
+		ints = append(ints, fmt.Sprintf("%d %v", i, v))
+	}
+
+	if expected := []string{"0 one", "1 2", "2 three", "3 4"}; fmt.Sprint(expected) != fmt.Sprint(all) {
+		wrong(t, "Slice", expected, all)
+	}
+	if expected := []string{"0 one", "2 three", "3 4"}; fmt.Sprint(expected) != fmt.Sprint(strings) {
+		wrong(t, "SliceStrings", expected, strings)
+	}
+	if expected := []string{"1 2", "3 4"}; fmt.Sprint(expected) != fmt.Sprint(ints) {
+		wrong(t, "SliceInts", expected, ints)
+	}
+
+	for range params.Slice("missing") {
+		wrong(t, "Slice", "no elements", "an element")
+	}
+}
@@ -0,0 +1,202 @@
+package whatever
+
+import (
+	"sort"
+	"strings"
+)
+
+// KeyOrder specifies the order of the keys returned
+// by NestedKeysWith and RangeKeys.
+type KeyOrder int
+
+const (
+	// Unordered returns the keys in map iteration order.
+	// It is the cheapest order, but it is random.
+	Unordered KeyOrder = iota
+	// Lexicographic sorts the keys segment by segment, so
+	// every object is followed by its nested keys.
+	Lexicographic
+	// Natural works as Lexicographic, but compares the numbers
+	// in the keys by value, so "item2" comes before "item10".
+	Natural
+	// ByDepth returns all top-level keys first, then all keys
+	// on the second level and so on. The keys on the same level
+	// are in lexicographic order.
+	ByDepth
+)
+
+// KeyOptions configures NestedKeysWith and RangeKeys.
+type KeyOptions struct {
+	// Order is the order in which the keys are returned.
+	Order KeyOrder
+	// LeavesOnly skips the keys of non-empty objects (and
+	// non-empty slices if SliceIndexes is set).
+	LeavesOnly bool
+	// SliceIndexes descends into slices and returns
+	// the index of every element, e.g. "list.0".
+	SliceIndexes bool
+	// MaxDepth limits the depth of the returned keys.
+	// 1 returns only the top-level keys, 0 means unlimited.
+	MaxDepth int
+}
+
+// NestedKeysWith works as NestedKeys, but the result
+// is configured by the options.
+// Example with LeavesOnly and SliceIndexes:
+//
+//	{"a": {"b": 1, "c": [1, 2]}}
+//
+// Will result in the following keys:
+//
+//	"a.b", "a.c.0", "a.c.1"
+func (p Params) NestedKeysWith(opts KeyOptions) []string {
+	var result []string
+	p.RangeKeys(opts, func(key string, value interface{}) bool {
+		result = append(result, key)
+		return true
+	})
+	return result
+}
+
+// RangeKeys calls fn for every key (in dotted notation) and its value
+// in the order and with the filters specified by the options.
+// If fn returns false, the iteration stops. Unlike NestedKeysWith
+// it does not build a slice of all keys, so it is preferable for
+// big structures.
+func (p Params) RangeKeys(opts KeyOptions, fn func(key string, value interface{}) bool) {
+	rangePaths(p, opts, func(path Path, value interface{}) bool {
+		return fn(path.String(), value)
+	})
+}
+
+// rangePaths visits the paths in p as specified by opts.
+func rangePaths(p Params, opts KeyOptions, fn func(path Path, value interface{}) bool) {
+	if opts.Order == ByDepth {
+		queue := children(nil, map[string]interface{}(p), opts)
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+			if !visitKey(node.path, node.value, opts, fn) {
+				return
+			}
+			queue = append(queue, children(node.path, node.value, opts)...)
+		}
+		return
+	}
+
+	rangeDepthFirst(nil, map[string]interface{}(p), opts, fn)
+}
+
+func rangeDepthFirst(path Path, value interface{}, opts KeyOptions, fn func(path Path, value interface{}) bool) bool {
+	if opts.Order == Unordered {
+		// Avoid building and sorting the list of children.
+		if m, ok := toMap(value); ok && withinDepth(path, opts) {
+			for k, v := range m {
+				child := path.Append(k)
+				if !visitKey(child, v, opts, fn) || !rangeDepthFirst(child, v, opts, fn) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	for _, child := range children(path, value, opts) {
+		if !visitKey(child.path, child.value, opts, fn) || !rangeDepthFirst(child.path, child.value, opts, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func visitKey(path Path, value interface{}, opts KeyOptions, fn func(path Path, value interface{}) bool) bool {
+	if opts.LeavesOnly && hasChildren(path, value, opts) {
+		return true
+	}
+	return fn(path, value)
+}
+
+func withinDepth(path Path, opts KeyOptions) bool {
+	return opts.MaxDepth <= 0 || len(path) < opts.MaxDepth
+}
+
+func hasChildren(path Path, value interface{}, opts KeyOptions) bool {
+	if !withinDepth(path, opts) {
+		return false
+	}
+	if m, ok := toMap(value); ok {
+		return len(m) > 0
+	}
+	s, ok := value.([]interface{})
+	return ok && opts.SliceIndexes && len(s) > 0
+}
+
+// children returns the ordered child nodes of value, which
+// should be visited according to opts.
+func children(path Path, value interface{}, opts KeyOptions) []queryNode {
+	if !withinDepth(path, opts) {
+		return nil
+	}
+
+	var result []queryNode
+	if m, ok := toMap(value); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		switch opts.Order {
+		case Natural:
+			sort.Slice(keys, func(i, j int) bool {
+				return naturalLess(keys[i], keys[j])
+			})
+		case Lexicographic, ByDepth:
+			sort.Strings(keys)
+		}
+		for _, k := range keys {
+			result = append(result, queryNode{path: path.Append(k), value: m[k]})
+		}
+	} else if s, ok := value.([]interface{}); ok && opts.SliceIndexes {
+		for i, v := range s {
+			result = append(result, queryNode{path: path.Append(i), value: v})
+		}
+	}
+	return result
+}
+
+// naturalLess compares a and b treating the runs
+// of digits in them as numbers.
+func naturalLess(a, b string) bool {
+	for a != "" && b != "" {
+		da, db := digitPrefix(a), digitPrefix(b)
+		if da == 0 || db == 0 {
+			if a[0] != b[0] {
+				return a[0] < b[0]
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		na := strings.TrimLeft(a[:da], "0")
+		nb := strings.TrimLeft(b[:db], "0")
+		if len(na) != len(nb) {
+			return len(na) < len(nb)
+		}
+		if na != nb {
+			return na < nb
+		}
+		if da != db {
+			// Equal values, fewer leading zeros first.
+			return da < db
+		}
+		a, b = a[da:], b[db:]
+	}
+	return len(a) < len(b)
+}
+
+func digitPrefix(s string) int {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return i
+}
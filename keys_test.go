@@ -0,0 +1,94 @@
+package whatever
+
+import (
+	"fmt"
+	"testing"
+)
+
+var keysParams = Params{
+	"item10": 1,
+	"item2":  Params{"b": 1, "a": []interface{}{"x", Params{"y": 1}}},
+	"item1":  map[string]interface{}{},
+}
+
+func TestParams_Keys_sorted(t *testing.T) {
+	expected := []string{"item1", "item10", "item2"}
+	if got := keysParams.Keys(); fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "Keys", expected, got)
+	}
+}
+
+func TestParams_NestedKeysWith(t *testing.T) {
+	cases := []struct {
+		opts     KeyOptions
+		expected []string
+	}{
+		{
+			KeyOptions{Order: Lexicographic},
+			[]string{"item1", "item10", "item2", "item2.a", "item2.b"},
+		},
+		{
+			KeyOptions{Order: Natural},
+			[]string{"item1", "item2", "item2.a", "item2.b", "item10"},
+		},
+		{
+			KeyOptions{Order: Natural, SliceIndexes: true},
+			[]string{"item1", "item2", "item2.a", "item2.a.0", "item2.a.1", "item2.a.1.y", "item2.b", "item10"},
+		},
+		{
+			KeyOptions{Order: ByDepth, SliceIndexes: true},
+			[]string{"item1", "item10", "item2", "item2.a", "item2.b", "item2.a.0", "item2.a.1", "item2.a.1.y"},
+		},
+		{
+			KeyOptions{Order: Lexicographic, LeavesOnly: true},
+			[]string{"item1", "item10", "item2.a", "item2.b"},
+		},
+		{
+			KeyOptions{Order: Lexicographic, LeavesOnly: true, SliceIndexes: true},
+			[]string{"item1", "item10", "item2.a.0", "item2.a.1.y", "item2.b"},
+		},
+		{
+			KeyOptions{Order: Natural, MaxDepth: 1},
+			[]string{"item1", "item2", "item10"},
+		},
+		{
+			KeyOptions{Order: Lexicographic, MaxDepth: 1, LeavesOnly: true},
+			[]string{"item1", "item10", "item2"},
+		},
+	}
+
+	for _, c := range cases {
+		got := keysParams.NestedKeysWith(c.opts)
+		if fmt.Sprint(c.expected) != fmt.Sprint(got) {
+			wrong(t, fmt.Sprintf("NestedKeysWith(%+v)", c.opts), c.expected, got)
+		}
+	}
+
+	got := keysParams.NestedKeysWith(KeyOptions{SliceIndexes: true})
+	expected := []string{"item1", "item10", "item2", "item2.a", "item2.a.0", "item2.a.1", "item2.a.1.y", "item2.b"}
+	if !equalSlicesStrings(expected, got) {
+		wrong(t, "NestedKeysWith", expected, got)
+	}
+}
+
+func TestParams_RangeKeys(t *testing.T) {
+	var got []string
+	keysParams.RangeKeys(KeyOptions{Order: Natural}, func(key string, value interface{}) bool {
+		got = append(got, key)
+		return key != "item2"
+	})
+
+	expected := []string{"item1", "item2"}
+	if fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "RangeKeys", expected, got)
+	}
+}
+
+func TestNaturalLess(t *testing.T) {
+	sorted := []string{"", "a", "a1", "a01", "a2", "a10", "a10b", "b", "b1c2", "b1c10"}
+	for i := 0; i < len(sorted)-1; i++ {
+		if !naturalLess(sorted[i], sorted[i+1]) || naturalLess(sorted[i+1], sorted[i]) {
+			wrong(t, "naturalLess", sorted[i]+" < "+sorted[i+1], false)
+		}
+	}
+}
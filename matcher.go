@@ -0,0 +1,287 @@
+package whatever
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KeyMatcher reports whether the key of a value in the
+// Params structure matches the key used for the lookup.
+type KeyMatcher func(lookup, key string) bool
+
+// Exact matches only identical keys. It is the default matcher.
+func Exact(lookup, key string) bool {
+	return lookup == key
+}
+
+// FoldCase matches keys case-insensitively, e.g. "UserID" and "userId".
+func FoldCase(lookup, key string) bool {
+	return strings.EqualFold(lookup, key)
+}
+
+// FoldCaseAndSeparators matches keys case-insensitively and
+// ignoring the underscores and dashes in them, so "UserID",
+// "userId", "user_id" and "user-id" are all the same key.
+func FoldCaseAndSeparators(lookup, key string) bool {
+	strip := func(s string) string {
+		return strings.NewReplacer("_", "", "-", "").Replace(s)
+	}
+	return strings.EqualFold(strip(lookup), strip(key))
+}
+
+// Aliases maps a key to its alternative names. The lookup of
+// any of the names will find the value stored under any other:
+//
+//	Aliases{"user_id": {"userId", "UserID"}}
+type Aliases map[string][]string
+
+// View is a read-only view of a Params structure that finds
+// the values using a KeyMatcher and Aliases instead of the exact
+// keys. All getters, GetP and Required honor them. The keys in
+// dotted notation are matched segment by segment.
+//
+// When more than one key matches, the exact key is preferred,
+// then the keys in the order of the aliases and finally the
+// keys in sorted order. A key in several groups of aliases
+// uses the groups in the order of their canonical keys. The function set with OnAmbiguous is
+// called for every such lookup.
+type View struct {
+	params      Params
+	matcher     KeyMatcher
+	aliases     Aliases
+	onAmbiguous func(key string, matches []string)
+}
+
+// WithKeyMatcher returns a View of the Params structure
+// that uses the matcher for the lookups.
+//
+//	p.WithKeyMatcher(whatever.FoldCase).GetInt("userid")
+func (p Params) WithKeyMatcher(matcher KeyMatcher) *View {
+	return &View{params: p, matcher: matcher}
+}
+
+// WithAliases returns a View of the Params structure
+// that uses the aliases for the lookups.
+func (p Params) WithAliases(aliases Aliases) *View {
+	return &View{params: p, matcher: Exact, aliases: aliases}
+}
+
+// WithKeyMatcher returns a copy of the View that uses the matcher.
+func (v *View) WithKeyMatcher(matcher KeyMatcher) *View {
+	view := *v
+	view.matcher = matcher
+	return &view
+}
+
+// WithAliases returns a copy of the View that uses the aliases.
+func (v *View) WithAliases(aliases Aliases) *View {
+	view := *v
+	view.aliases = aliases
+	return &view
+}
+
+// OnAmbiguous returns a copy of the View that calls fn every
+// time more than one key matches a lookup. It receives the
+// looked up key and all matched keys, the first being used.
+func (v *View) OnAmbiguous(fn func(key string, matches []string)) *View {
+	view := *v
+	view.onAmbiguous = fn
+	return &view
+}
+
+// Params returns the underlying Params structure.
+func (v *View) Params() Params {
+	return v.params
+}
+
+// Key returns the actual key in the underlying Params
+// that matches the top-level key and true, or the key
+// itself and false if nothing matches.
+func (v *View) Key(key string) (string, bool) {
+	return v.resolve(v.params, key)
+}
+
+// names returns the key and all its aliases. If the key is in
+// more than one group, the groups are ordered by their canonical
+// keys, so the result doesn't depend on the map iteration order.
+func (v *View) names(key string) []string {
+	canonicals := make([]string, 0, len(v.aliases))
+	for canonical := range v.aliases {
+		canonicals = append(canonicals, canonical)
+	}
+	sort.Strings(canonicals)
+
+	names := []string{key}
+	for _, canonical := range canonicals {
+		group := append([]string{canonical}, v.aliases[canonical]...)
+		if !contains(group, key) {
+			continue
+		}
+		for _, el := range group {
+			if !contains(names, el) {
+				names = append(names, el)
+			}
+		}
+	}
+	return names
+}
+
+func (v *View) resolve(set map[string]interface{}, key string) (string, bool) {
+	matcher := v.matcher
+	if matcher == nil {
+		matcher = Exact
+	}
+
+	names := v.names(key)
+	var matches []string
+	for _, name := range names {
+		if _, ok := set[name]; ok && !contains(matches, name) {
+			matches = append(matches, name)
+		}
+	}
+
+	var loose []string
+	for k := range set {
+		if contains(matches, k) {
+			continue
+		}
+		for _, name := range names {
+			if matcher(name, k) {
+				loose = append(loose, k)
+				break
+			}
+		}
+	}
+	sort.Strings(loose)
+	matches = append(matches, loose...)
+
+	if len(matches) == 0 {
+		return key, false
+	}
+
+	if len(matches) > 1 && v.onAmbiguous != nil {
+		v.onAmbiguous(key, matches)
+	}
+	return matches[0], true
+}
+
+// resolvePath resolves the dotted key segment by segment and
+// returns the object that holds the value and the actual key.
+func (v *View) resolvePath(key string) (map[string]interface{}, string, bool) {
+	var set map[string]interface{} = v.params
+	segments := strings.Split(key, ".")
+	for i, segment := range segments {
+		actual, ok := v.resolve(set, segment)
+		if !ok {
+			return nil, "", false
+		}
+		if i == len(segments)-1 {
+			return set, actual, true
+		}
+		if set, ok = toMap(set[actual]); !ok {
+			return nil, "", false
+		}
+	}
+	return nil, "", false
+}
+
+func contains(list []string, s string) bool {
+	for _, el := range list {
+		if el == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Required works as Params.Required, but uses
+// the matcher and the aliases of the View.
+func (v *View) Required(keys ...string) error {
+	for _, key := range keys {
+		set, actual, ok := v.resolvePath(key)
+		if !ok || !exists(set, actual) {
+			return fmt.Errorf("the parameter %s is required", key)
+		}
+	}
+	return nil
+}
+
+// GetP returns a View of the nested Params with the
+// specified key that uses the same matcher and aliases.
+func (v *View) GetP(key string) *View {
+	view := *v
+	view.params = v.params.GetP(v.key(key))
+	return &view
+}
+
+func (v *View) key(key string) string {
+	actual, _ := v.resolve(v.params, key)
+	return actual
+}
+
+// GetI works as Params.GetI.
+func (v *View) GetI(key string) interface{} {
+	return v.params.GetI(v.key(key))
+}
+
+// Get works as Params.Get.
+func (v *View) Get(key string) string {
+	return v.params.Get(v.key(key))
+}
+
+// GetString works as Params.GetString.
+func (v *View) GetString(key string) string {
+	return v.params.GetString(v.key(key))
+}
+
+// GetInt works as Params.GetInt.
+func (v *View) GetInt(key string) int {
+	return v.params.GetInt(v.key(key))
+}
+
+// GetInt8 works as Params.GetInt8.
+func (v *View) GetInt8(key string) int8 {
+	return v.params.GetInt8(v.key(key))
+}
+
+// GetInt64 works as Params.GetInt64.
+func (v *View) GetInt64(key string) int64 {
+	return v.params.GetInt64(v.key(key))
+}
+
+// GetFloat works as Params.GetFloat.
+func (v *View) GetFloat(key string) float32 {
+	return v.params.GetFloat(v.key(key))
+}
+
+// GetFloat32 works as Params.GetFloat32.
+func (v *View) GetFloat32(key string) float32 {
+	return v.params.GetFloat32(v.key(key))
+}
+
+// GetFloat64 works as Params.GetFloat64.
+func (v *View) GetFloat64(key string) float64 {
+	return v.params.GetFloat64(v.key(key))
+}
+
+// GetTime works as Params.GetTime.
+func (v *View) GetTime(key string) time.Time {
+	return v.params.GetTime(v.key(key))
+}
+
+// GetSlice works as Params.GetSlice.
+func (v *View) GetSlice(key string) []interface{} {
+	return v.params.GetSlice(v.key(key))
+}
+
+// GetSliceStrings works as Params.GetSliceStrings.
+func (v *View) GetSliceStrings(key string) []string {
+	return v.params.GetSliceStrings(v.key(key))
+}
+
+// GetSliceInts works as Params.GetSliceInts.
+func (v *View) GetSliceInts(key string) []int {
+	return v.params.GetSliceInts(v.key(key))
+}
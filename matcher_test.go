@@ -0,0 +1,126 @@
+package whatever
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestKeyMatchers(t *testing.T) {
+	cases := []struct {
+		matcher  KeyMatcher
+		lookup   string
+		key      string
+		expected bool
+	}{
+		{Exact, "userId", "userId", true},
+		{Exact, "userId", "UserID", false},
+		{FoldCase, "userId", "UserID", true},
+		{FoldCase, "userId", "user_id", false},
+		{FoldCaseAndSeparators, "userId", "user_id", true},
+		{FoldCaseAndSeparators, "USER-ID", "user_id", true},
+		{FoldCaseAndSeparators, "userId", "user", false},
+	}
+
+	for _, c := range cases {
+		if got := c.matcher(c.lookup, c.key); got != c.expected {
+			wrong(t, fmt.Sprintf("KeyMatcher(%s, %s)", c.lookup, c.key), c.expected, got)
+		}
+	}
+}
+
+func TestParams_WithKeyMatcher(t *testing.T) {
+	p := Params{
+		"UserID":  10,
+		"Profile": Params{"FirstName": "John", "empty": ""},
+	}
+	v := p.WithKeyMatcher(FoldCase)
+
+	if got := v.GetInt("userid"); got != 10 {
+		wrong(t, "View.GetInt", 10, got)
+	}
+
+	if got := v.GetP("profile").Get("firstname"); got != "John" {
+		wrong(t, "View.GetP", "John", got)
+	}
+
+	if err := v.Required("userID", "profile.firstNAME"); err != nil {
+		wrong(t, "View.Required", nil, err)
+	}
+
+	for _, key := range []string{"profile.empty", "profile.missing", "missing.key", "userid.nested"} {
+		if err := v.Required(key); err == nil {
+			wrong(t, "View.Required", "the parameter "+key+" is required", nil)
+		}
+	}
+
+	if got := p.WithKeyMatcher(Exact).GetInt("userid"); got != 0 {
+		wrong(t, "View.GetInt", 0, got)
+	}
+}
+
+func TestParams_WithAliases(t *testing.T) {
+	p := Params{"userId": 10, "nested": Params{"UserID": 20}}
+	v := p.WithAliases(Aliases{"user_id": {"userId", "UserID"}})
+
+	if got := v.GetInt("user_id"); got != 10 {
+		wrong(t, "View.GetInt", 10, got)
+	}
+
+	if got := v.GetInt("UserID"); got != 10 {
+		wrong(t, "View.GetInt", 10, got)
+	}
+
+	if err := v.Required("nested.user_id"); err != nil {
+		wrong(t, "View.Required", nil, err)
+	}
+
+	if got := v.GetP("nested").GetInt("userId"); got != 20 {
+		wrong(t, "View.GetP", 20, got)
+	}
+}
+
+func TestView_aliasGroups(t *testing.T) {
+	p := Params{"userId": 1, "uid": 2}
+	aliases := Aliases{"user_id": {"userId"}, "id": {"user_id", "uid"}}
+
+	for i := 0; i < 100; i++ {
+		v := p.WithAliases(aliases)
+		if key, _ := v.Key("user_id"); key != "uid" {
+			t.Fatalf("View.Key expected uid from the group of id, got %s", key)
+		}
+		if got := v.GetInt("user_id"); got != 2 {
+			t.Fatalf("View.GetInt expected 2, got %d", got)
+		}
+	}
+}
+
+func TestView_OnAmbiguous(t *testing.T) {
+	p := Params{"user_id": 1, "userId": 2, "UserID": 3}
+
+	var warnings []string
+	v := p.WithAliases(Aliases{"user_id": {"userId"}}).
+		WithKeyMatcher(FoldCase).
+		OnAmbiguous(func(key string, matches []string) {
+			warnings = append(warnings, fmt.Sprintf("%s: %v", key, matches))
+		})
+
+	if got := v.GetInt("userId"); got != 2 {
+		wrong(t, "View.GetInt", 2, got)
+	}
+
+	if got := v.GetInt("user_id"); got != 1 {
+		wrong(t, "View.GetInt", 1, got)
+	}
+
+	expected := []string{
+		"userId: [userId user_id UserID]",
+		"user_id: [user_id userId UserID]",
+	}
+	if fmt.Sprint(expected) != fmt.Sprint(warnings) {
+		wrong(t, "View.OnAmbiguous", expected, warnings)
+	}
+
+	if key, ok := v.Key("USERID"); !ok || key != "UserID" {
+		wrong(t, "View.Key", "UserID", key)
+	}
+}
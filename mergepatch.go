@@ -0,0 +1,77 @@
+package whatever
+
+// MergePatch applies a JSON Merge Patch (RFC 7386) to the
+// Params structure. Unlike Merge it works recursively:
+//
+//   - a nil value in the patch deletes the key,
+//   - an object in the patch is merged into the object with
+//     the same key (a non-object value is replaced with one),
+//   - every other value replaces the existing one.
+//
+// The patch values are copied, so the patch can be reused.
+func (p Params) MergePatch(patch Params) {
+	mergePatchMap(p, patch)
+}
+
+// CreateMergePatch returns a JSON Merge Patch (RFC 7386) that
+// transforms original into modified when applied with MergePatch.
+//
+// Keep in mind that merge patches cannot express setting a
+// value to nil, so such values in modified will be deleted
+// instead when the patch is applied.
+func CreateMergePatch(original, modified Params) Params {
+	return createMergePatch(original, modified)
+}
+
+func mergePatchMap(target, patch map[string]interface{}) {
+	for k, v := range patch {
+		if v == nil {
+			delete(target, k)
+			continue
+		}
+		target[k] = mergePatchValue(target[k], v)
+	}
+}
+
+func mergePatchValue(target, patch interface{}) interface{} {
+	patchMap, ok := toMap(patch)
+	if !ok {
+		return copyValue(patch)
+	}
+
+	targetMap, ok := toMap(target)
+	if !ok {
+		targetMap = Params{}
+	}
+	mergePatchMap(targetMap, patchMap)
+	return targetMap
+}
+
+func createMergePatch(original, modified map[string]interface{}) Params {
+	patch := Params{}
+	for k := range original {
+		if _, ok := modified[k]; !ok {
+			patch[k] = nil
+		}
+	}
+
+	for k, mv := range modified {
+		ov, ok := original[k]
+		if !ok {
+			patch[k] = copyValue(mv)
+			continue
+		}
+
+		om, oIsMap := toMap(ov)
+		mm, mIsMap := toMap(mv)
+		if oIsMap && mIsMap {
+			if nested := createMergePatch(om, mm); !nested.Empty() {
+				patch[k] = nested
+			}
+		} else if !jsonEqual(ov, mv) {
+			patch[k] = copyValue(mv)
+		}
+	}
+
+	return patch
+}
@@ -0,0 +1,76 @@
+package whatever
+
+import "testing"
+
+func TestParams_MergePatch(t *testing.T) {
+	// Test cases from RFC 7386, Appendix A.
+	cases := []struct {
+		original, patch, expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, c := range cases {
+		p := parse([]byte(c.original))
+		p.MergePatch(parse([]byte(c.patch)))
+		expected := parse([]byte(c.expected))
+		if !jsonEqual(expected, p) {
+			wrong(t, "MergePatch", expected, p)
+		}
+	}
+}
+
+func TestParams_MergePatch_copiesValues(t *testing.T) {
+	patch := Params{"nested": Params{"one": 1}}
+	p := Params{}
+	p.MergePatch(patch)
+	p.GetP("nested").Add("one", 10)
+
+	if got := patch.GetP("nested").GetInt("one"); got != 1 {
+		wrong(t, "MergePatch", 1, got)
+	}
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	original := parse([]byte(`{
+		"same": 1,
+		"removed": true,
+		"changed": "old",
+		"list": [1, 2],
+		"nested": {"one": 1, "two": 2, "same": {"x": 1}}
+	}`))
+	modified := parse([]byte(`{
+		"same": 1,
+		"changed": "new",
+		"added": {"x": 1},
+		"list": [1, 2, 3],
+		"nested": {"one": 1, "two": 3, "same": {"x": 1}}
+	}`))
+
+	patch := CreateMergePatch(original, modified)
+	expected := Params{
+		"removed": nil,
+		"changed": "new",
+		"added":   Params{"x": 1},
+		"list":    []interface{}{1, 2, 3},
+		"nested":  Params{"two": 3},
+	}
+
+	if !jsonEqual(expected, patch) {
+		wrong(t, "CreateMergePatch", expected, patch)
+	}
+
+	original.MergePatch(patch)
+	if !jsonEqual(modified, original) {
+		wrong(t, "CreateMergePatch", modified, original)
+	}
+}
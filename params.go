@@ -184,8 +184,13 @@ func (p Params) GetFloat(key string) float32 {
 // or in other words the JSON format for the Date object in JavaScript.
 // The value should look like this:
 //     "2015-02-27T21:53:57.582Z"
+// If the value is already a time.Time (for example
+// decoded from YAML or TOML) it is returned as is.
 // Otherwise returns time.Time{}
 func (p Params) GetTime(key string) time.Time {
+	if t, ok := p[key].(time.Time); ok {
+		return t
+	}
 	if result, err := time.Parse(time.RFC3339, p.Get(key)); err == nil {
 		return result
 	}
@@ -268,9 +273,12 @@ func (p Params) GetSliceInts(key string) []int {
 // and suffix "]". The key from the previous example will now be:
 //     some_key[inner_key][last_key]
 //
+// The slices are added as multiple values under their key, using
+// the same notation for the slices in nested Params, e.g. some_key[list].
+//
 // If the Params structure is blank, then an empty url.Values will be returned.
 func (p Params) URLValues(prefix, suffix string) url.Values {
-	return toURLValues(p, prefix, suffix, false)
+	return toURLValues(p, prefix, suffix)
 }
 
 // Required will return an error if one of the passed keys is missing
@@ -291,21 +299,24 @@ func (p Params) Required(keys ...string) error {
 	return nil
 }
 
-// Keys will return the top-level keys of the params.
+// Keys will return the top-level keys of the params
+// sorted lexicographically.
 // Keep in mind if you need the nested keys as well you can
 // use NestedKeys.
 func (p Params) Keys() []string {
-	return keys(p)
+	return sortedKeys(p)
 }
 
 // NestedKeys will return all keys in the params map.
-// The nested keys will be prefixed with a dot.
+// The nested keys will be prefixed with a dot and will follow
+// their parent key. The keys on each level are sorted.
 // Example:
 //     { "one": { "two": 3 } }
 // Will result in the following key:
 //     "one.two"
+// To configure the order and the returned keys use NestedKeysWith.
 func (p Params) NestedKeys() []string {
-	return nestedKeys(p, "", false)
+	return nestedKeys(p)
 }
 
 // Merge merges two params objects (the parameter can
@@ -357,83 +368,146 @@ func exists(input map[string]interface{}, key string) (ok bool) {
 	return ok
 }
 
-func stringify(v interface{}) string {
-	if vs, ok := v.(string); ok {
-		return vs
+// lookup returns the value under the dotted key in input.
+// The second result reports whether the key was found.
+func lookup(input map[string]interface{}, key string) (interface{}, bool) {
+	if input == nil {
+		return nil, false
 	}
 
-	return fmt.Sprintf("%v", v)
+	if index := strings.Index(key, "."); index != -1 {
+		nested, ok := toMap(input[key[:index]])
+		if !ok {
+			return nil, false
+		}
+		return lookup(nested, key[index+1:])
+	}
+
+	v, ok := input[key]
+	return v, ok
 }
 
-func keys(set Params) (result []string) {
-	for k := range set {
-		result = append(result, k)
+// store sets the value under the dotted key in input, creating
+// the missing intermediate Params along the way. An existing
+// intermediate value that is not a map will be replaced.
+func store(input map[string]interface{}, key string, value interface{}) {
+	if index := strings.Index(key, "."); index != -1 {
+		nested, ok := toMap(input[key[:index]])
+		if !ok {
+			created := Params{}
+			input[key[:index]] = created
+			nested = created
+		}
+		store(nested, key[index+1:], value)
+		return
 	}
-	return
+
+	input[key] = value
 }
 
-func nestedKeys(set map[string]interface{}, parent string, subParse bool) []string {
-	var key string
-	var result []string
-	for k, v := range set {
+// canStore reports whether store can set the value under the
+// dotted key without replacing an existing intermediate value
+// that is not a map, such as a scalar or a slice.
+func canStore(input map[string]interface{}, key string) bool {
+	index := strings.Index(key, ".")
+	if index == -1 {
+		return true
+	}
 
-		if subParse {
-			key = fmt.Sprintf("%s.%s", parent, k)
-		} else {
-			key = k
-		}
+	next, ok := input[key[:index]]
+	if !ok {
+		return true
+	}
+	nested, ok := toMap(next)
+	return ok && canStore(nested, key[index+1:])
+}
 
-		result = append(result, key)
+// toMap returns v as map[string]interface{} if it is
+// either Params or map[string]interface{}.
+func toMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case Params:
+		return m, m != nil
+	case map[string]interface{}:
+		return m, m != nil
+	}
+	return nil, false
+}
 
-		if val, ok := v.(map[string]interface{}); ok {
-			subKeys := nestedKeys(val, key, true)
-			result = append(result, subKeys...)
-		} else if val, ok := v.(Params); ok {
-			subKeys := nestedKeys(val, key, true)
-			result = append(result, subKeys...)
+// copyValue returns a deep copy of v. Params, maps and
+// slices of interface{} are copied recursively, everything
+// else is returned as is.
+func copyValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case Params:
+		result := make(Params, len(val))
+		for k, el := range val {
+			result[k] = copyValue(el)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for k, el := range val {
+			result[k] = copyValue(el)
 		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, el := range val {
+			result[i] = copyValue(el)
+		}
+		return result
+	}
+	return v
+}
 
+func stringify(v interface{}) string {
+	if vs, ok := v.(string); ok {
+		return vs
 	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+func nestedKeys(set Params) []string {
+	var result []string
+	set.Walk(func(path Path, value interface{}) error {
+		result = append(result, path.String())
+		if _, ok := value.([]interface{}); ok {
+			return SkipDir
+		}
+		return nil
+	})
 	return result
 }
 
-func toURLValues(set Params, prefix, suffix string, subParse bool) url.Values {
+func toURLValues(set Params, prefix, suffix string) url.Values {
 	if prefix == "" {
 		prefix = "."
 		suffix = ""
 	}
 
 	result := url.Values{}
-	var subset url.Values
-	for key, value := range set {
-		var foundSubset bool
-		if v, ok := value.(Params); ok {
-			subset = toURLValues(v, prefix, suffix, true)
-			foundSubset = true
-		} else if v, ok := value.(map[string]interface{}); ok {
-			subset = toURLValues(Params(v), prefix, suffix, true)
-			foundSubset = true
-		} else if v, ok := value.([]interface{}); ok {
+	set.Walk(func(path Path, value interface{}) error {
+		switch value.(type) {
+		case Params, map[string]interface{}:
+			return nil
+		}
+
+		key := fmt.Sprint(path[0])
+		for _, el := range path[1:] {
+			key = fmt.Sprintf("%s%s%v%s", key, prefix, el, suffix)
+		}
+
+		if v, ok := value.([]interface{}); ok {
 			for _, el := range v {
 				result[key] = append(result[key], stringify(el))
 			}
-			continue
+			return SkipDir
 		}
-		if foundSubset {
-			for k, v := range subset {
-				nestedKey := fmt.Sprintf("%s%s", key, k)
-				if subParse {
-					nestedKey = fmt.Sprintf("%s%s%s%s", prefix, key, suffix, k)
-				}
-				result[nestedKey] = append(result[nestedKey], v...)
-			}
-		} else {
-			valueKey := key
-			if subParse {
-				valueKey = fmt.Sprintf("%s%s%s", prefix, key, suffix)
-			}
-			result[valueKey] = append(result[valueKey], stringify(value))
-		}
-	}
+
+		result[key] = append(result[key], stringify(value))
+		return nil
+	})
 	return result
 }
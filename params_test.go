@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -323,6 +325,23 @@ func TestParams_URLValues(t *testing.T) {
 	}
 }
 
+func TestParams_URLValues_nestedSlices(t *testing.T) {
+	params := Params{
+		"a":    Params{"b": []interface{}{1, 2}},
+		"list": []interface{}{"x", "y"},
+	}
+
+	expected := url.Values{"a.b": {"1", "2"}, "list": {"x", "y"}}
+	if got := params.URLValues("", ""); !reflect.DeepEqual(expected, got) {
+		wrong(t, "URLValues", expected, got)
+	}
+
+	expected = url.Values{"a[b]": {"1", "2"}, "list": {"x", "y"}}
+	if got := params.URLValues("[", "]"); !reflect.DeepEqual(expected, got) {
+		wrong(t, "URLValues", expected, got)
+	}
+}
+
 func TestParams_URLValues_withPrefixAndSufix(t *testing.T) {
 	keys := []string{
 		"int",
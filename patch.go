@@ -0,0 +1,263 @@
+package whatever
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Operation is a single JSON Patch (RFC 6902) operation.
+// Op is one of "add", "remove", "replace", "move", "copy" and "test".
+// Path and From are JSON Pointers (RFC 6901).
+type Operation struct {
+	Op    string
+	Path  string
+	From  string
+	Value interface{}
+}
+
+// Patch is a JSON Patch document - a list of operations
+// that are applied in order. It can be marshaled to and
+// unmarshaled from JSON.
+type Patch []Operation
+
+// NewPatchFromJSON decodes a JSON Patch document.
+func NewPatchFromJSON(jsonBody []byte) (Patch, error) {
+	var patch Patch
+	err := json.Unmarshal(jsonBody, &patch)
+	return patch, err
+}
+
+// MarshalJSON encodes the operation with only the
+// members that are meaningful for its type.
+func (o Operation) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{"op": o.Op, "path": o.Path}
+	switch o.Op {
+	case "add", "replace", "test":
+		m["value"] = o.Value
+	case "move", "copy":
+		m["from"] = o.From
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes the operation and checks that
+// all members required by its type are present.
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	var op Operation
+	for _, member := range []string{"op", "path"} {
+		raw, ok := m[member]
+		if !ok {
+			return fmt.Errorf("the operation member %s is required", member)
+		}
+		target := &op.Op
+		if member == "path" {
+			target = &op.Path
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return err
+		}
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		raw, ok := m["value"]
+		if !ok {
+			return fmt.Errorf("the operation member value is required for %s", op.Op)
+		}
+		if err := json.Unmarshal(raw, &op.Value); err != nil {
+			return err
+		}
+	case "move", "copy":
+		raw, ok := m["from"]
+		if !ok {
+			return fmt.Errorf("the operation member from is required for %s", op.Op)
+		}
+		if err := json.Unmarshal(raw, &op.From); err != nil {
+			return err
+		}
+	case "remove":
+	default:
+		return fmt.Errorf("unknown operation %q", op.Op)
+	}
+
+	*o = op
+	return nil
+}
+
+// ApplyPatch applies the JSON Patch to the Params structure.
+// The patch is applied atomically - either all operations
+// succeed or the Params structure is left unchanged and the
+// error for the first failed operation is returned.
+//
+// Paths are JSON Pointers that can reference nested Params,
+// map[string]interface{} and []interface{} values.
+func (p Params) ApplyPatch(patch Patch) error {
+	var doc interface{} = copyValue(p)
+	for i, op := range patch {
+		var err error
+		if doc, err = applyOperation(doc, op); err != nil {
+			return fmt.Errorf("the operation %d (%s %s) failed: %v", i, op.Op, op.Path, err)
+		}
+	}
+
+	result, ok := toMap(doc)
+	if !ok {
+		return fmt.Errorf("the patch result is not an object")
+	}
+
+	for k := range p {
+		delete(p, k)
+	}
+	for k, v := range result {
+		p[k] = v
+	}
+	return nil
+}
+
+func applyOperation(doc interface{}, op Operation) (interface{}, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add":
+		return addPointer(doc, path, copyValue(op.Value))
+	case "remove":
+		doc, _, err = removePointer(doc, path)
+		return doc, err
+	case "replace":
+		return replacePointer(doc, path, copyValue(op.Value))
+	case "move", "copy":
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if op.Op == "move" {
+			if isPrefix(from, path) && len(from) != len(path) {
+				return nil, fmt.Errorf("cannot move %s into its own child", op.From)
+			}
+			if doc, value, err = removePointer(doc, from); err != nil {
+				return nil, err
+			}
+		} else {
+			if value, err = getPointer(doc, from); err != nil {
+				return nil, err
+			}
+			value = copyValue(value)
+		}
+		return addPointer(doc, path, value)
+	case "test":
+		value, err := getPointer(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(value, op.Value) {
+			return nil, fmt.Errorf("the value at %s is not equal to %v", op.Path, op.Value)
+		}
+		return doc, nil
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", op.Op)
+}
+
+// Diff returns a JSON Patch that transforms a into b.
+// The patch consists only of add, remove and replace
+// operations and the operations are in deterministic order.
+// It is the same as Compare(a, b).Patch().
+func Diff(a, b Params) Patch {
+	return Compare(a, b).Patch()
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(set map[string]interface{}) []string {
+	result := make([]string, 0, len(set))
+	for k := range set {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// jsonEqual compares two values the way they would compare
+// after being encoded as JSON - Params and map[string]interface{}
+// are the same and all numbers are compared by value.
+func jsonEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+func normalizeJSON(v interface{}) interface{} {
+	if m, ok := toMap(v); ok {
+		result := make(map[string]interface{}, len(m))
+		for k, el := range m {
+			result[k] = normalizeJSON(el)
+		}
+		return result
+	}
+
+	if s, ok := v.([]interface{}); ok {
+		result := make([]interface{}, len(s))
+		for i, el := range s {
+			result[i] = normalizeJSON(el)
+		}
+		return result
+	}
+
+	if f, ok := toFloat(v); ok {
+		return f
+	}
+	return v
+}
+
+// toFloat converts any of the Go numeric types to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,150 @@
+package whatever
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParams_ApplyPatch(t *testing.T) {
+	p := parse([]byte(`{
+		"foo": "bar",
+		"a/b": 1,
+		"list": [1, 2, 3],
+		"nested": {"one": 1, "two": 2}
+	}`))
+
+	patch, err := NewPatchFromJSON([]byte(`[
+		{"op": "test", "path": "/foo", "value": "bar"},
+		{"op": "add", "path": "/baz", "value": "qux"},
+		{"op": "replace", "path": "/a~1b", "value": 10},
+		{"op": "add", "path": "/list/1", "value": 10},
+		{"op": "add", "path": "/list/-", "value": 20},
+		{"op": "remove", "path": "/list/0"},
+		{"op": "move", "from": "/nested/one", "path": "/moved"},
+		{"op": "copy", "from": "/nested", "path": "/copied"},
+		{"op": "test", "path": "/copied", "value": {"two": 2}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.ApplyPatch(patch); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := parse([]byte(`{
+		"foo": "bar",
+		"baz": "qux",
+		"a/b": 10,
+		"list": [10, 2, 3, 20],
+		"nested": {"two": 2},
+		"moved": 1,
+		"copied": {"two": 2}
+	}`))
+
+	if !jsonEqual(expected, p) {
+		wrong(t, "ApplyPatch", expected, p)
+	}
+}
+
+func TestParams_ApplyPatch_atomic(t *testing.T) {
+	p := Params{"one": 1, "list": []interface{}{1}}
+	patch := Patch{
+		{Op: "add", Path: "/two", Value: 2},
+		{Op: "remove", Path: "/list/0"},
+		{Op: "test", Path: "/one", Value: 2},
+	}
+
+	if err := p.ApplyPatch(patch); err == nil {
+		wrong(t, "ApplyPatch", "an error", nil)
+	}
+
+	if !jsonEqual(Params{"one": 1, "list": []interface{}{1}}, p) {
+		wrong(t, "ApplyPatch", Params{"one": 1, "list": []interface{}{1}}, p)
+	}
+}
+
+func TestParams_ApplyPatch_errors(t *testing.T) {
+	patches := []Patch{
+		{{Op: "remove", Path: "/missing"}},
+		{{Op: "replace", Path: "/missing", Value: 1}},
+		{{Op: "add", Path: "/missing/key", Value: 1}},
+		{{Op: "add", Path: "/list/5", Value: 1}},
+		{{Op: "add", Path: "/list/01", Value: 1}},
+		{{Op: "move", From: "/nested", Path: "/nested/child"}},
+		{{Op: "add", Path: "no-slash", Value: 1}},
+		{{Op: "add", Path: "/bad~2escape", Value: 1}},
+		{{Op: "add", Path: "", Value: 1}},
+		{{Op: "unknown", Path: "/one"}},
+	}
+
+	for _, patch := range patches {
+		p := Params{"one": 1, "list": []interface{}{1}, "nested": Params{}}
+		if err := p.ApplyPatch(patch); err == nil {
+			wrong(t, "ApplyPatch", "an error", nil)
+		}
+	}
+}
+
+func TestNewPatchFromJSON_errors(t *testing.T) {
+	docs := []string{
+		`[{"op": "add", "path": "/a"}]`,
+		`[{"op": "move", "path": "/a"}]`,
+		`[{"path": "/a"}]`,
+		`[{"op": "nope", "path": "/a"}]`,
+	}
+
+	for _, doc := range docs {
+		if _, err := NewPatchFromJSON([]byte(doc)); err == nil {
+			wrong(t, "NewPatchFromJSON", "an error", nil)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := parse([]byte(`{
+		"same": 1,
+		"removed": true,
+		"changed": "old",
+		"list": [1, 2, 3],
+		"short": [1],
+		"nested": {"one": 1, "two": 2}
+	}`))
+	b := Params{
+		"same":    1,
+		"changed": "new",
+		"added":   Params{"x": 1},
+		"list":    []interface{}{1, 5},
+		"short":   []interface{}{1, 2},
+		"nested":  map[string]interface{}{"one": 1, "two": 3},
+	}
+
+	patch := Diff(a, b)
+	expected := Patch{
+		{Op: "remove", Path: "/removed"},
+		{Op: "add", Path: "/added", Value: Params{"x": 1}},
+		{Op: "replace", Path: "/changed", Value: "new"},
+		{Op: "replace", Path: "/list/1", Value: 5},
+		{Op: "remove", Path: "/list/2"},
+		{Op: "replace", Path: "/nested/two", Value: 3},
+		{Op: "add", Path: "/short/1", Value: 2},
+	}
+
+	got, _ := json.Marshal(patch)
+	want, _ := json.Marshal(expected)
+	if string(got) != string(want) {
+		wrong(t, "Diff", string(want), string(got))
+	}
+
+	if err := a.ApplyPatch(patch); err != nil {
+		t.Fatal(err)
+	}
+
+	if !jsonEqual(a, b) {
+		wrong(t, "Diff", b, a)
+	}
+
+	if patch := Diff(b, b); len(patch) != 0 {
+		wrong(t, "Diff", Patch{}, patch)
+	}
+}
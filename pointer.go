@@ -0,0 +1,388 @@
+package whatever
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pointer returns the value referenced by the JSON Pointer (RFC 6901).
+// Unlike the dotted notation, pointers can address keys that contain
+// dots and the elements of slices:
+//
+//	p.Pointer("/a/b~1c/0")
+//
+// will return the first element of the slice with key "b/c" in the
+// nested object "a". The empty pointer references the Params itself.
+// Returns an error if the pointer is malformed or the value is missing.
+func (p Params) Pointer(pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	return getPointer(p, tokens)
+}
+
+// SetPointer sets the value referenced by the JSON Pointer.
+// Missing objects on the way are created as Params. Slice elements
+// can be replaced by index and a new element can be appended with
+// the "-" token (or the index equal to the length of the slice).
+func (p Params) SetPointer(pointer string, value interface{}) error {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("cannot set the root of the document")
+	}
+	_, err = setPointer(p, tokens, value)
+	return err
+}
+
+// DeletePointer deletes the value referenced by the JSON Pointer and
+// returns it. Deleting a slice element shifts the following elements.
+// Returns an error if the value is missing.
+func (p Params) DeletePointer(pointer string) (interface{}, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("cannot delete the root of the document")
+	}
+	_, removed, err := removePointer(p, tokens)
+	return removed, err
+}
+
+// PointerToDotted converts a JSON Pointer to the dotted notation
+// used by Required and NestedKeys. Returns an error for the empty
+// pointer and for pointers with keys that contain dots, because
+// they cannot be represented in the dotted notation.
+func PointerToDotted(pointer string) (string, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("the root pointer has no dotted representation")
+	}
+	for _, token := range tokens {
+		if strings.Contains(token, ".") {
+			return "", fmt.Errorf("the key %q cannot be represented in dotted notation", token)
+		}
+	}
+	return strings.Join(tokens, "."), nil
+}
+
+// DottedToPointer converts a key in dotted notation to a JSON Pointer.
+// Example:
+//
+//	"some_key.nested/key.last_key"
+//
+// Will result in:
+//
+//	"/some_key/nested~1key/last_key"
+func DottedToPointer(key string) string {
+	return formatPointer(strings.Split(key, "."))
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into its
+// unescaped reference tokens. The empty pointer references
+// the whole document and results in no tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if pointer[0] != '/' {
+		return nil, fmt.Errorf("the pointer %q should start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				continue
+			}
+			if j+1 >= len(token) || (token[j+1] != '0' && token[j+1] != '1') {
+				return nil, fmt.Errorf("the pointer %q has an invalid escape sequence", pointer)
+			}
+		}
+		tokens[i] = unescapeToken(token)
+	}
+
+	return tokens, nil
+}
+
+// formatPointer joins the reference tokens into a JSON Pointer.
+func formatPointer(tokens []string) string {
+	var buf strings.Builder
+	for _, token := range tokens {
+		buf.WriteByte('/')
+		buf.WriteString(escapeToken(token))
+	}
+	return buf.String()
+}
+
+func escapeToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
+}
+
+func unescapeToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+}
+
+// arrayIndex parses an array index reference token.
+// Leading zeros are not allowed. If allowEnd is true the
+// token "-" and the index equal to size are accepted and
+// both mean the position after the last element.
+func arrayIndex(token string, size int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return size, nil
+	}
+
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	if index > size || (index == size && !allowEnd) {
+		return 0, fmt.Errorf("array index %d is out of range", index)
+	}
+
+	return index, nil
+}
+
+// getPointer returns the value referenced by the tokens in node.
+func getPointer(node interface{}, tokens []string) (interface{}, error) {
+	for i, token := range tokens {
+		if m, ok := toMap(node); ok {
+			v, found := m[token]
+			if !found {
+				return nil, missingPath(tokens[:i+1], nil)
+			}
+			node = v
+		} else if s, ok := node.([]interface{}); ok {
+			index, err := arrayIndex(token, len(s), false)
+			if err != nil {
+				return nil, missingPath(tokens[:i+1], err)
+			}
+			node = s[index]
+		} else {
+			return nil, missingPath(tokens[:i+1], nil)
+		}
+	}
+	return node, nil
+}
+
+// addPointer adds value at the location referenced by the tokens
+// following the semantics of the JSON Patch "add" operation:
+// object members are created or replaced and array elements are
+// inserted. The parent of the location must exist. It returns
+// the new node, because inserting into a slice may reallocate it.
+func addPointer(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	token := tokens[0]
+	if m, ok := toMap(node); ok {
+		if len(tokens) == 1 {
+			m[token] = value
+			return m, nil
+		}
+		child, found := m[token]
+		if !found {
+			return nil, missingPath(tokens[:1], nil)
+		}
+		child, err := addPointer(child, tokens[1:], value)
+		if err != nil {
+			return nil, withParent(token, err)
+		}
+		m[token] = child
+		return m, nil
+	}
+
+	if s, ok := node.([]interface{}); ok {
+		if len(tokens) == 1 {
+			index, err := arrayIndex(token, len(s), true)
+			if err != nil {
+				return nil, missingPath(tokens[:1], err)
+			}
+			s = append(s, nil)
+			copy(s[index+1:], s[index:])
+			s[index] = value
+			return s, nil
+		}
+		index, err := arrayIndex(token, len(s), false)
+		if err != nil {
+			return nil, missingPath(tokens[:1], err)
+		}
+		child, err := addPointer(s[index], tokens[1:], value)
+		if err != nil {
+			return nil, withParent(token, err)
+		}
+		s[index] = child
+		return s, nil
+	}
+
+	return nil, missingPath(tokens[:1], nil)
+}
+
+// setPointer stores value at the location referenced by the tokens,
+// creating the missing objects on the way. It returns the new node,
+// because appending to a slice may reallocate it.
+func setPointer(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	token := tokens[0]
+	if m, ok := toMap(node); ok {
+		child, found := m[token]
+		if !found && len(tokens) > 1 {
+			child = Params{}
+		}
+		child, err := setPointer(child, tokens[1:], value)
+		if err != nil {
+			return nil, withParent(token, err)
+		}
+		m[token] = child
+		return m, nil
+	}
+
+	if s, ok := node.([]interface{}); ok {
+		index, err := arrayIndex(token, len(s), true)
+		if err != nil {
+			return nil, missingPath(tokens[:1], err)
+		}
+		if index == len(s) {
+			s = append(s, nil)
+			if len(tokens) > 1 {
+				s[index] = Params{}
+			}
+		}
+		child, err := setPointer(s[index], tokens[1:], value)
+		if err != nil {
+			return nil, withParent(token, err)
+		}
+		s[index] = child
+		return s, nil
+	}
+
+	return nil, missingPath(tokens[:1], nil)
+}
+
+// removePointer removes the value at the location referenced by
+// the tokens. It returns the new node and the removed value.
+func removePointer(node interface{}, tokens []string) (interface{}, interface{}, error) {
+	if len(tokens) == 0 {
+		return nil, node, nil
+	}
+
+	token := tokens[0]
+	if m, ok := toMap(node); ok {
+		child, found := m[token]
+		if !found {
+			return nil, nil, missingPath(tokens[:1], nil)
+		}
+		if len(tokens) == 1 {
+			delete(m, token)
+			return m, child, nil
+		}
+		child, removed, err := removePointer(child, tokens[1:])
+		if err != nil {
+			return nil, nil, withParent(token, err)
+		}
+		m[token] = child
+		return m, removed, nil
+	}
+
+	if s, ok := node.([]interface{}); ok {
+		index, err := arrayIndex(token, len(s), false)
+		if err != nil {
+			return nil, nil, missingPath(tokens[:1], err)
+		}
+		if len(tokens) == 1 {
+			removed := s[index]
+			return append(s[:index], s[index+1:]...), removed, nil
+		}
+		child, removed, err := removePointer(s[index], tokens[1:])
+		if err != nil {
+			return nil, nil, withParent(token, err)
+		}
+		s[index] = child
+		return s, removed, nil
+	}
+
+	return nil, nil, missingPath(tokens[:1], nil)
+}
+
+// replacePointer replaces the existing value at the location
+// referenced by the tokens.
+func replacePointer(node interface{}, tokens []string, value interface{}) (interface{}, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	parent, err := getPointer(node, tokens[:len(tokens)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := tokens[len(tokens)-1]
+	if m, ok := toMap(parent); ok {
+		if _, found := m[last]; !found {
+			return nil, missingPath(tokens, nil)
+		}
+		m[last] = value
+		return node, nil
+	}
+
+	if s, ok := parent.([]interface{}); ok {
+		index, err := arrayIndex(last, len(s), false)
+		if err != nil {
+			return nil, missingPath(tokens, err)
+		}
+		s[index] = value
+		return node, nil
+	}
+
+	return nil, missingPath(tokens, nil)
+}
+
+// pointerError is a failure at the location referenced by
+// tokens. The recursive calls prepend the tokens of the parents
+// with withParent, so the error always names the full pointer.
+type pointerError struct {
+	tokens []string
+	err    error
+}
+
+func (e *pointerError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("the path %s does not exist: %v", formatPointer(e.tokens), e.err)
+	}
+	return fmt.Sprintf("the path %s does not exist", formatPointer(e.tokens))
+}
+
+func (e *pointerError) Unwrap() error {
+	return e.err
+}
+
+func missingPath(tokens []string, err error) error {
+	return &pointerError{tokens: append([]string(nil), tokens...), err: err}
+}
+
+// withParent prepends the token of the parent
+// to the path of a pointerError.
+func withParent(token string, err error) error {
+	if e, ok := err.(*pointerError); ok {
+		e.tokens = append([]string{token}, e.tokens...)
+	}
+	return err
+}
@@ -0,0 +1,181 @@
+package whatever
+
+import "testing"
+
+var pointerBody = []byte(`{
+	"foo": ["bar", "baz"],
+	"": 0,
+	"a/b": 1,
+	"c%d": 2,
+	"e^f": 3,
+	"g|h": 4,
+	"i\\j": 5,
+	"k\"l": 6,
+	" ": 7,
+	"m~n": 8,
+	"dotted.key": {"x": 9}
+}`)
+
+func TestParams_Pointer(t *testing.T) {
+	// Examples from RFC 6901, Section 5.
+	p := parse(pointerBody)
+	expected := map[string]interface{}{
+		"/foo/0":        "bar",
+		"/":             0,
+		"/a~1b":         1,
+		"/c%d":          2,
+		"/e^f":          3,
+		"/g|h":          4,
+		"/i\\j":         5,
+		"/k\"l":         6,
+		"/ ":            7,
+		"/m~0n":         8,
+		"/dotted.key/x": 9,
+	}
+
+	for pointer, value := range expected {
+		got, err := p.Pointer(pointer)
+		if err != nil {
+			t.Error(err)
+		}
+		if !jsonEqual(value, got) {
+			wrong(t, "Pointer", value, got)
+		}
+	}
+
+	if got, _ := p.Pointer(""); !jsonEqual(p, got) {
+		wrong(t, "Pointer", p, got)
+	}
+
+	for _, pointer := range []string{"foo", "/missing", "/foo/2", "/foo/-", "/foo/01", "/m~2n", "/a~1b/x"} {
+		if _, err := p.Pointer(pointer); err == nil {
+			wrong(t, "Pointer", "an error for "+pointer, nil)
+		}
+	}
+}
+
+func TestParams_SetPointer(t *testing.T) {
+	p := Params{"list": []interface{}{Params{"one": 1}}}
+
+	sets := map[string]interface{}{
+		"/list/0/one":      10,
+		"/list/-":          "appended",
+		"/new/nested/key":  "value",
+		"/dotted.key~1x/y": true,
+	}
+	for pointer, value := range sets {
+		if err := p.SetPointer(pointer, value); err != nil {
+			t.Error(err)
+		}
+	}
+
+	expected := parse([]byte(`{
+		"list": [{"one": 10}, "appended"],
+		"new": {"nested": {"key": "value"}},
+		"dotted.key/x": {"y": true}
+	}`))
+	if !jsonEqual(expected, p) {
+		wrong(t, "SetPointer", expected, p)
+	}
+
+	for _, pointer := range []string{"", "/list/5", "/list/0/one/deeper"} {
+		if err := p.SetPointer(pointer, 1); err == nil {
+			wrong(t, "SetPointer", "an error for "+pointer, nil)
+		}
+	}
+}
+
+func TestParams_DeletePointer(t *testing.T) {
+	p := parse(pointerBody)
+
+	v, err := p.DeletePointer("/foo/0")
+	if err != nil || v != "bar" {
+		wrong(t, "DeletePointer", "bar", v)
+	}
+
+	if got := p.GetSliceStrings("foo"); !equalSlicesStrings([]string{"baz"}, got) {
+		wrong(t, "DeletePointer", []string{"baz"}, got)
+	}
+
+	if _, err := p.DeletePointer("/dotted.key/x"); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := p.DeletePointer("/dotted.key/x"); err == nil {
+		wrong(t, "DeletePointer", "an error", nil)
+	}
+
+	if _, err := p.DeletePointer(""); err == nil {
+		wrong(t, "DeletePointer", "an error", nil)
+	}
+}
+
+func TestPointerToDotted(t *testing.T) {
+	expected := map[string]string{
+		"/one":           "one",
+		"/one/two/three": "one.two.three",
+		"/a~1b/c~0d":     "a/b.c~d",
+		"/list/0":        "list.0",
+	}
+
+	for pointer, dotted := range expected {
+		got, err := PointerToDotted(pointer)
+		if err != nil || got != dotted {
+			wrong(t, "PointerToDotted", dotted, got)
+		}
+	}
+
+	for _, pointer := range []string{"", "/dotted.key", "invalid"} {
+		if _, err := PointerToDotted(pointer); err == nil {
+			wrong(t, "PointerToDotted", "an error for "+pointer, nil)
+		}
+	}
+}
+
+func TestDottedToPointer(t *testing.T) {
+	expected := map[string]string{
+		"one":           "/one",
+		"one.two.three": "/one/two/three",
+		"a/b.c~d":       "/a~1b/c~0d",
+		"":              "/",
+	}
+
+	for dotted, pointer := range expected {
+		if got := DottedToPointer(dotted); got != pointer {
+			wrong(t, "DottedToPointer", pointer, got)
+		}
+	}
+}
+
+func TestPointerErrors(t *testing.T) {
+	p := Params{"a": Params{"list": []interface{}{Params{"b": 1}}}}
+
+	tests := []struct {
+		run      func() error
+		expected string
+	}{
+		{func() error { _, err := p.Pointer("/a/list/5"); return err },
+			"the path /a/list/5 does not exist: array index 5 is out of range"},
+		{func() error { _, err := p.Pointer("/a/list/0/c"); return err },
+			"the path /a/list/0/c does not exist"},
+		{func() error { return p.SetPointer("/a/list/x/b", 2) },
+			`the path /a/list/x does not exist: invalid array index "x"`},
+		{func() error { return p.SetPointer("/a/list/0/b/c", 2) },
+			"the path /a/list/0/b/c does not exist"},
+		{func() error { _, err := p.DeletePointer("/a/list/3/b"); return err },
+			"the path /a/list/3 does not exist: array index 3 is out of range"},
+		{func() error {
+			return p.ApplyPatch(Patch{{Op: "add", Path: "/a/list/0/missing/x", Value: 1}})
+		}, "the operation 0 (add /a/list/0/missing/x) failed: the path /a/list/0/missing does not exist"},
+		{func() error {
+			return p.ApplyPatch(Patch{{Op: "replace", Path: "/a/list/7", Value: 1}})
+		}, "the operation 0 (replace /a/list/7) failed: the path /a/list/7 does not exist: array index 7 is out of range"},
+	}
+
+	for i, test := range tests {
+		err := test.run()
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("test %d: expected %q, got %v", i, test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package whatever
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pick returns a deep copy of the Params structure that contains only
+// the values under the specified paths. The paths are in dotted notation
+// and can contain wildcards ("*") that match every key of an object or
+// every element of a slice, and indexes of slice elements:
+//
+//	p.Pick("id", "user.name", "items.*.price")
+//
+// The receiver is not modified.
+func (p Params) Pick(paths ...string) Params {
+	patterns := splitPatterns(paths)
+	result := Params{}
+	for k, v := range p {
+		if picked, ok := pickValue(Path{k}, v, patterns); ok {
+			result[k] = picked
+		}
+	}
+	return result
+}
+
+// Omit returns a deep copy of the Params structure without the values
+// under the specified paths. The paths can contain wildcards as with Pick:
+//
+//	p.Omit("internal", "users.*.password")
+//
+// The receiver is not modified. To remove the values in place use Remove.
+func (p Params) Omit(paths ...string) Params {
+	result := copyValue(p).(Params)
+	result.Remove(paths...)
+	return result
+}
+
+// Remove works as Omit, but removes the values from the
+// receiver instead of returning a copy.
+func (p Params) Remove(paths ...string) {
+	patterns := splitPatterns(paths)
+	for k, v := range p {
+		if matchAny(Path{k}, patterns) {
+			delete(p, k)
+		} else {
+			p[k] = removeValue(Path{k}, v, patterns)
+		}
+	}
+}
+
+// Rename returns a deep copy of the Params structure where the last key
+// of the from path is renamed to the key to. The parent keys of the from
+// path can be wildcards, so a key can be renamed in all elements of a slice:
+//
+//	p.Rename("users.*.userId", "user_id")
+//
+// An existing value with the new key is overwritten.
+// The receiver is not modified.
+func (p Params) Rename(from, to string) Params {
+	result := copyValue(p).(Params)
+	pattern := strings.Split(from, ".")
+	last := pattern[len(pattern)-1]
+
+	var parents []map[string]interface{}
+	if len(pattern) == 1 {
+		parents = append(parents, result)
+	}
+	result.Walk(func(path Path, value interface{}) error {
+		if len(path) >= len(pattern)-1 {
+			if m, ok := toMap(value); ok && matchPattern(path, pattern[:len(pattern)-1]) {
+				parents = append(parents, m)
+			}
+			return SkipDir
+		}
+		return nil
+	})
+
+	for _, parent := range parents {
+		if v, ok := parent[last]; ok && last != to {
+			parent[to] = v
+			delete(parent, last)
+		}
+	}
+	return result
+}
+
+// Move returns a deep copy of the Params structure where the value
+// under the from path is moved under the to path. Both paths are in
+// dotted notation without wildcards. The missing objects on the to
+// path are created. If the from path is missing, the copy is unchanged.
+// The receiver is not modified.
+func (p Params) Move(from, to string) Params {
+	result := copyValue(p).(Params)
+	value, ok := lookup(result, from)
+	if !ok {
+		return result
+	}
+
+	result.Remove(from)
+	store(result, to, value)
+	return result
+}
+
+func splitPatterns(paths []string) [][]string {
+	patterns := make([][]string, len(paths))
+	for i, path := range paths {
+		patterns[i] = strings.Split(path, ".")
+	}
+	return patterns
+}
+
+// matchPattern checks if path matches the pattern exactly.
+func matchPattern(path Path, pattern []string) bool {
+	return len(path) == len(pattern) && matchPrefix(path, pattern)
+}
+
+// matchPrefix checks if the path matches the beginning of the pattern.
+func matchPrefix(path Path, pattern []string) bool {
+	if len(path) > len(pattern) {
+		return false
+	}
+	for i, el := range path {
+		if pattern[i] != "*" && pattern[i] != fmt.Sprint(el) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchAny(path Path, patterns [][]string) bool {
+	for _, pattern := range patterns {
+		if matchPattern(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor checks if path is a proper prefix of any pattern.
+func isAncestor(path Path, patterns [][]string) bool {
+	for _, pattern := range patterns {
+		if len(path) < len(pattern) && matchPrefix(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// pickValue returns a copy of value with only the picked paths.
+// The second result is false if nothing was picked.
+func pickValue(path Path, value interface{}, patterns [][]string) (interface{}, bool) {
+	if matchAny(path, patterns) {
+		return copyValue(value), true
+	}
+	if !isAncestor(path, patterns) {
+		return nil, false
+	}
+
+	if m, ok := toMap(value); ok {
+		result := Params{}
+		for k, v := range m {
+			if picked, ok := pickValue(path.Append(k), v, patterns); ok {
+				result[k] = picked
+			}
+		}
+		return result, len(result) > 0
+	}
+
+	if s, ok := value.([]interface{}); ok {
+		var result []interface{}
+		for i, v := range s {
+			if picked, ok := pickValue(path.Append(i), v, patterns); ok {
+				result = append(result, picked)
+			}
+		}
+		return result, len(result) > 0
+	}
+
+	return nil, false
+}
+
+// removeValue removes the matched paths from the children of value.
+// It returns the new value, because removing slice elements
+// changes the length of the slice.
+func removeValue(path Path, value interface{}, patterns [][]string) interface{} {
+	if !isAncestor(path, patterns) {
+		return value
+	}
+
+	if m, ok := toMap(value); ok {
+		for k, v := range m {
+			child := path.Append(k)
+			if matchAny(child, patterns) {
+				delete(m, k)
+			} else {
+				m[k] = removeValue(child, v, patterns)
+			}
+		}
+		return m
+	}
+
+	if s, ok := value.([]interface{}); ok {
+		result := s[:0]
+		for i, v := range s {
+			child := path.Append(i)
+			if !matchAny(child, patterns) {
+				result = append(result, removeValue(child, v, patterns))
+			}
+		}
+		return result
+	}
+
+	return value
+}
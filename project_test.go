@@ -0,0 +1,114 @@
+package whatever
+
+import "testing"
+
+var projectBody = []byte(`{
+	"id": 1,
+	"internal": {"token": "secret"},
+	"user": {"name": "John", "password": "secret", "userId": 10},
+	"items": [
+		{"name": "a", "price": 10, "cost": 5},
+		{"name": "b", "price": 20, "cost": 15}
+	]
+}`)
+
+func TestParams_Pick(t *testing.T) {
+	p := parse(projectBody)
+	got := p.Pick("id", "user.name", "items.*.price", "missing", "user.name.deeper")
+
+	expected := parse([]byte(`{
+		"id": 1,
+		"user": {"name": "John"},
+		"items": [{"price": 10}, {"price": 20}]
+	}`))
+	if !jsonEqual(expected, got) {
+		wrong(t, "Pick", expected, got)
+	}
+
+	got = p.Pick("items.1")
+	expected = parse([]byte(`{"items": [{"name": "b", "price": 20, "cost": 15}]}`))
+	if !jsonEqual(expected, got) {
+		wrong(t, "Pick", expected, got)
+	}
+
+	got.GetSlice("items")[0].(map[string]interface{})["name"] = "changed"
+	if !jsonEqual(parse(projectBody), p) {
+		wrong(t, "Pick", "unchanged receiver", p)
+	}
+}
+
+func TestParams_Omit(t *testing.T) {
+	p := parse(projectBody)
+	got := p.Omit("internal", "user.password", "items.*.cost", "missing.key")
+
+	expected := parse([]byte(`{
+		"id": 1,
+		"user": {"name": "John", "userId": 10},
+		"items": [{"name": "a", "price": 10}, {"name": "b", "price": 20}]
+	}`))
+	if !jsonEqual(expected, got) {
+		wrong(t, "Omit", expected, got)
+	}
+
+	if !jsonEqual(parse(projectBody), p) {
+		wrong(t, "Omit", "unchanged receiver", p)
+	}
+}
+
+func TestParams_Remove(t *testing.T) {
+	p := parse(projectBody)
+	p.Remove("items.0", "user.*")
+
+	expected := parse([]byte(`{
+		"id": 1,
+		"internal": {"token": "secret"},
+		"user": {},
+		"items": [{"name": "b", "price": 20, "cost": 15}]
+	}`))
+	if !jsonEqual(expected, p) {
+		wrong(t, "Remove", expected, p)
+	}
+}
+
+func TestParams_Rename(t *testing.T) {
+	p := parse(projectBody)
+	got := p.Rename("user.userId", "user_id").Rename("items.*.price", "amount").Rename("id", "ID")
+
+	expected := parse([]byte(`{
+		"ID": 1,
+		"internal": {"token": "secret"},
+		"user": {"name": "John", "password": "secret", "user_id": 10},
+		"items": [
+			{"name": "a", "amount": 10, "cost": 5},
+			{"name": "b", "amount": 20, "cost": 15}
+		]
+	}`))
+	if !jsonEqual(expected, got) {
+		wrong(t, "Rename", expected, got)
+	}
+
+	if !jsonEqual(parse(projectBody), p) {
+		wrong(t, "Rename", "unchanged receiver", p)
+	}
+}
+
+func TestParams_Move(t *testing.T) {
+	p := parse(projectBody)
+	got := p.Move("user.password", "credentials.password").Move("missing", "other")
+
+	if got.GetP("credentials").Get("password") != "secret" {
+		wrong(t, "Move", "secret", got.GetP("credentials").Get("password"))
+	}
+
+	if err := got.Required("user.password"); err == nil {
+		wrong(t, "Move", "the parameter user.password is required", nil)
+	}
+
+	if err := got.Required("other"); err == nil {
+		wrong(t, "Move", "the parameter other is required", nil)
+	}
+
+	if err := p.Required("user.password"); err != nil {
+		wrong(t, "Move", nil, err)
+	}
+}
@@ -0,0 +1,767 @@
+package whatever
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Match is a single result of a Query.
+// Path is the normalized JSONPath of the value
+// (e.g. $['items'][0]['price']) and Pointer is
+// the same location as a JSON Pointer.
+type Match struct {
+	Path    string
+	Pointer string
+	Value   interface{}
+}
+
+// Query evaluates a JSONPath expression (RFC 9535) against
+// the Params structure and returns all matched values together
+// with their paths. The following subset is supported:
+//
+//	$                 the root
+//	.name, ['name']   child member
+//	*, [*]            all children
+//	..name, ..*       recursive descent
+//	[0], [-1]         array element
+//	[1:5:2]           array slice
+//	['a', 0]          union of selectors
+//	[?@.qty > 1]      filter with ==, !=, <, <=, >, >=, &&, || and !
+//
+// Filters can use relative (@) and absolute ($) paths, string,
+// number, true, false and null literals. A path alone in a filter
+// tests for existence. Function extensions are not supported.
+//
+// The children of objects are visited in sorted key order,
+// so the order of the results is deterministic.
+// Returns an error if the expression cannot be parsed.
+//
+// Example:
+//
+//	p.Query("$.items[?@.qty > 1].price")
+func (p Params) Query(expr string) ([]Match, error) {
+	parser := &queryParser{expr: expr}
+	segments, err := parser.parseQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := evalSegments(segments, []queryNode{{value: p}}, p)
+	result := make([]Match, len(nodes))
+	for i, node := range nodes {
+		result[i] = Match{
+			Path:    node.normalizedPath(),
+			Pointer: node.path.Pointer(),
+			Value:   node.value,
+		}
+	}
+	return result, nil
+}
+
+type queryNode struct {
+	path  Path
+	value interface{}
+}
+
+func (n queryNode) child(key interface{}, value interface{}) queryNode {
+	return queryNode{path: n.path.Append(key), value: value}
+}
+
+// children returns the child nodes of n in document
+// order - sorted keys for objects and index order for arrays.
+func (n queryNode) children() []queryNode {
+	var result []queryNode
+	if m, ok := toMap(n.value); ok {
+		for _, k := range sortedKeys(m) {
+			result = append(result, n.child(k, m[k]))
+		}
+	} else if s, ok := n.value.([]interface{}); ok {
+		for i, v := range s {
+			result = append(result, n.child(i, v))
+		}
+	}
+	return result
+}
+
+// descendants returns n and all its descendants in pre-order.
+func (n queryNode) descendants() []queryNode {
+	result := []queryNode{n}
+	for _, child := range n.children() {
+		result = append(result, child.descendants()...)
+	}
+	return result
+}
+
+func (n queryNode) normalizedPath() string {
+	var buf strings.Builder
+	buf.WriteByte('$')
+	for _, el := range n.path {
+		if index, ok := el.(int); ok {
+			fmt.Fprintf(&buf, "[%d]", index)
+		} else {
+			buf.WriteString("['")
+			buf.WriteString(escapeQueryName(el.(string)))
+			buf.WriteString("']")
+		}
+	}
+	return buf.String()
+}
+
+func escapeQueryName(name string) string {
+	var buf strings.Builder
+	for _, r := range name {
+		switch r {
+		case '\'':
+			buf.WriteString(`\'`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	return buf.String()
+}
+
+type querySegment struct {
+	descendant bool
+	selectors  []querySelector
+}
+
+type querySelector interface {
+	selectFrom(node queryNode, root interface{}) []queryNode
+}
+
+type nameSelector string
+
+func (s nameSelector) selectFrom(node queryNode, root interface{}) []queryNode {
+	if m, ok := toMap(node.value); ok {
+		if v, found := m[string(s)]; found {
+			return []queryNode{node.child(string(s), v)}
+		}
+	}
+	return nil
+}
+
+type wildcardSelector struct{}
+
+func (wildcardSelector) selectFrom(node queryNode, root interface{}) []queryNode {
+	return node.children()
+}
+
+type indexSelector int
+
+func (s indexSelector) selectFrom(node queryNode, root interface{}) []queryNode {
+	if slice, ok := node.value.([]interface{}); ok {
+		index := int(s)
+		if index < 0 {
+			index += len(slice)
+		}
+		if index >= 0 && index < len(slice) {
+			return []queryNode{node.child(index, slice[index])}
+		}
+	}
+	return nil
+}
+
+type sliceSelector struct {
+	start, end, step *int
+}
+
+func (s sliceSelector) selectFrom(node queryNode, root interface{}) []queryNode {
+	slice, ok := node.value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	step := 1
+	if s.step != nil {
+		step = *s.step
+	}
+	if step == 0 {
+		return nil
+	}
+
+	size := len(slice)
+	normalize := func(i int) int {
+		if i < 0 {
+			return size + i
+		}
+		return i
+	}
+	clamp := func(i, min, max int) int {
+		if i < min {
+			return min
+		}
+		if i > max {
+			return max
+		}
+		return i
+	}
+
+	var result []queryNode
+	if step > 0 {
+		start, end := 0, size
+		if s.start != nil {
+			start = normalize(*s.start)
+		}
+		if s.end != nil {
+			end = normalize(*s.end)
+		}
+		for i := clamp(start, 0, size); i < clamp(end, 0, size); i += step {
+			result = append(result, node.child(i, slice[i]))
+		}
+	} else {
+		start, end := size-1, -size-1
+		if s.start != nil {
+			start = normalize(*s.start)
+		}
+		if s.end != nil {
+			end = normalize(*s.end)
+		}
+		for i := clamp(start, -1, size-1); i > clamp(end, -1, size-1); i += step {
+			result = append(result, node.child(i, slice[i]))
+		}
+	}
+	return result
+}
+
+type filterSelector struct {
+	expr filterExpr
+}
+
+func (s filterSelector) selectFrom(node queryNode, root interface{}) []queryNode {
+	var result []queryNode
+	for _, child := range node.children() {
+		if s.expr.test(child.value, root) {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+func evalSegments(segments []querySegment, nodes []queryNode, root interface{}) []queryNode {
+	for _, segment := range segments {
+		var next []queryNode
+		for _, node := range nodes {
+			candidates := []queryNode{node}
+			if segment.descendant {
+				candidates = node.descendants()
+			}
+			for _, candidate := range candidates {
+				for _, selector := range segment.selectors {
+					next = append(next, selector.selectFrom(candidate, root)...)
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+type filterExpr interface {
+	test(current, root interface{}) bool
+}
+
+type orExpr struct{ left, right filterExpr }
+
+func (e orExpr) test(current, root interface{}) bool {
+	return e.left.test(current, root) || e.right.test(current, root)
+}
+
+type andExpr struct{ left, right filterExpr }
+
+func (e andExpr) test(current, root interface{}) bool {
+	return e.left.test(current, root) && e.right.test(current, root)
+}
+
+type notExpr struct{ expr filterExpr }
+
+func (e notExpr) test(current, root interface{}) bool {
+	return !e.expr.test(current, root)
+}
+
+type existsExpr struct{ path pathOperand }
+
+func (e existsExpr) test(current, root interface{}) bool {
+	return len(e.path.nodes(current, root)) > 0
+}
+
+type compareExpr struct {
+	op          string
+	left, right filterOperand
+}
+
+func (e compareExpr) test(current, root interface{}) bool {
+	a, aok := e.left.value(current, root)
+	b, bok := e.right.value(current, root)
+
+	equal := func() bool {
+		if !aok || !bok {
+			return aok == bok
+		}
+		return jsonEqual(a, b)
+	}
+	less := func(a, b interface{}) bool {
+		if !aok || !bok {
+			return false
+		}
+		if fa, ok := toFloat(a); ok {
+			fb, ok := toFloat(b)
+			return ok && fa < fb
+		}
+		if sa, ok := a.(string); ok {
+			sb, ok := b.(string)
+			return ok && sa < sb
+		}
+		return false
+	}
+
+	switch e.op {
+	case "==":
+		return equal()
+	case "!=":
+		return !equal()
+	case "<":
+		return less(a, b)
+	case "<=":
+		return less(a, b) || equal()
+	case ">":
+		return less(b, a)
+	case ">=":
+		return less(b, a) || equal()
+	}
+	return false
+}
+
+// filterOperand is a literal or a path in a filter comparison.
+// The second result is false if the path selects nothing.
+type filterOperand interface {
+	value(current, root interface{}) (interface{}, bool)
+}
+
+type literalOperand struct{ v interface{} }
+
+func (o literalOperand) value(current, root interface{}) (interface{}, bool) {
+	return o.v, true
+}
+
+type pathOperand struct {
+	absolute bool
+	segments []querySegment
+}
+
+func (o pathOperand) nodes(current, root interface{}) []queryNode {
+	start := current
+	if o.absolute {
+		start = root
+	}
+	return evalSegments(o.segments, []queryNode{{value: start}}, root)
+}
+
+func (o pathOperand) value(current, root interface{}) (interface{}, bool) {
+	nodes := o.nodes(current, root)
+	if len(nodes) != 1 {
+		return nil, false
+	}
+	return nodes[0].value, true
+}
+
+type queryParser struct {
+	expr string
+	pos  int
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid query %q at position %d: %s", p.expr, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) eof() bool {
+	return p.pos >= len(p.expr)
+}
+
+func (p *queryParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.expr[p.pos]
+}
+
+func (p *queryParser) skipSpaces() {
+	for !p.eof() && strings.IndexByte(" \t\n\r", p.expr[p.pos]) != -1 {
+		p.pos++
+	}
+}
+
+func (p *queryParser) consume(s string) bool {
+	if strings.HasPrefix(p.expr[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) parseQuery() ([]querySegment, error) {
+	p.skipSpaces()
+	if !p.consume("$") {
+		return nil, p.errorf("the query should start with $")
+	}
+
+	segments, err := p.parseSegments()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if !p.eof() {
+		return nil, p.errorf("unexpected %q", p.peek())
+	}
+	return segments, nil
+}
+
+func (p *queryParser) parseSegments() ([]querySegment, error) {
+	var segments []querySegment
+	for {
+		start := p.pos
+		p.skipSpaces()
+
+		var segment querySegment
+		switch {
+		case p.consume(".."):
+			segment.descendant = true
+			if p.peek() == '[' {
+				p.pos++
+				selectors, err := p.parseBracket()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = selectors
+			} else {
+				selector, err := p.parseShorthand()
+				if err != nil {
+					return nil, err
+				}
+				segment.selectors = []querySelector{selector}
+			}
+		case p.consume("."):
+			selector, err := p.parseShorthand()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = []querySelector{selector}
+		case p.consume("["):
+			selectors, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segment.selectors = selectors
+		default:
+			p.pos = start
+			return segments, nil
+		}
+
+		segments = append(segments, segment)
+	}
+}
+
+// parseShorthand parses the selector after . or .. - a member name or *.
+func (p *queryParser) parseShorthand() (querySelector, error) {
+	if p.consume("*") {
+		return wildcardSelector{}, nil
+	}
+
+	start := p.pos
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.expr[p.pos:])
+		isName := r == '_' || r >= 0x80 ||
+			(r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9' && p.pos != start)
+		if !isName {
+			break
+		}
+		p.pos += size
+	}
+
+	if start == p.pos {
+		return nil, p.errorf("expected a member name")
+	}
+	return nameSelector(p.expr[start:p.pos]), nil
+}
+
+// parseBracket parses the comma separated selectors after [.
+func (p *queryParser) parseBracket() ([]querySelector, error) {
+	var selectors []querySelector
+	for {
+		p.skipSpaces()
+		selector, err := p.parseSelector()
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, selector)
+
+		p.skipSpaces()
+		if p.consume("]") {
+			return selectors, nil
+		}
+		if !p.consume(",") {
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *queryParser) parseSelector() (querySelector, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return wildcardSelector{}, nil
+	case c == '\'' || c == '"':
+		name, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return nameSelector(name), nil
+	case c == '?':
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return filterSelector{expr: expr}, nil
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		return p.parseIndexOrSlice()
+	}
+	return nil, p.errorf("unexpected %q", p.peek())
+}
+
+func (p *queryParser) parseIndexOrSlice() (querySelector, error) {
+	var parts [3]*int
+	part := 0
+	for {
+		p.skipSpaces()
+		if c := p.peek(); c == '-' || (c >= '0' && c <= '9') {
+			n, err := p.parseInt()
+			if err != nil {
+				return nil, err
+			}
+			parts[part] = &n
+			p.skipSpaces()
+		}
+
+		if part == 2 || !p.consume(":") {
+			break
+		}
+		part++
+	}
+
+	if part == 0 {
+		if parts[0] == nil {
+			return nil, p.errorf("expected an index")
+		}
+		return indexSelector(*parts[0]), nil
+	}
+	return sliceSelector{start: parts[0], end: parts[1], step: parts[2]}, nil
+}
+
+func (p *queryParser) parseInt() (int, error) {
+	start := p.pos
+	p.consume("-")
+	for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+
+	n, err := strconv.Atoi(p.expr[start:p.pos])
+	if err != nil {
+		p.pos = start
+		return 0, p.errorf("invalid integer")
+	}
+	return n, nil
+}
+
+func (p *queryParser) parseString() (string, error) {
+	quote := p.peek()
+	p.pos++
+
+	var buf strings.Builder
+	for !p.eof() {
+		c := p.expr[p.pos]
+		p.pos++
+		if c == quote {
+			return buf.String(), nil
+		}
+		if c != '\\' {
+			buf.WriteByte(c)
+			continue
+		}
+
+		if p.eof() {
+			break
+		}
+		escaped := p.expr[p.pos]
+		p.pos++
+		switch escaped {
+		case 'b':
+			buf.WriteByte('\b')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case '/', '\\', '\'', '"':
+			buf.WriteByte(escaped)
+		case 'u':
+			if p.pos+4 > len(p.expr) {
+				return "", p.errorf("invalid unicode escape")
+			}
+			r, err := strconv.ParseUint(p.expr[p.pos:p.pos+4], 16, 32)
+			if err != nil {
+				return "", p.errorf("invalid unicode escape")
+			}
+			p.pos += 4
+			buf.WriteRune(rune(r))
+		default:
+			return "", p.errorf("invalid escape sequence \\%c", escaped)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *queryParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpaces()
+		if !p.consume("||") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+}
+
+func (p *queryParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpaces()
+		if !p.consume("&&") {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+}
+
+func (p *queryParser) parseUnary() (filterExpr, error) {
+	p.skipSpaces()
+	if p.peek() == '!' && !strings.HasPrefix(p.expr[p.pos:], "!=") {
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr}, nil
+	}
+
+	if p.consume("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if !p.consume(")") {
+			return nil, p.errorf("expected )")
+		}
+		return expr, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.consume(op) {
+			continue
+		}
+		p.skipSpaces()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareExpr{op: op, left: left, right: right}, nil
+	}
+
+	path, ok := left.(pathOperand)
+	if !ok {
+		return nil, p.errorf("a literal cannot be used as a test")
+	}
+	return existsExpr{path}, nil
+}
+
+func (p *queryParser) parseOperand() (filterOperand, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.parseSegments()
+		if err != nil {
+			return nil, err
+		}
+		return pathOperand{absolute: c == '$', segments: segments}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		return literalOperand{s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.consume("-")
+		for !p.eof() && strings.IndexByte("0123456789.eE+-", p.peek()) != -1 {
+			p.pos++
+		}
+		f, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("invalid number")
+		}
+		return literalOperand{f}, nil
+	case p.consume("true"):
+		return literalOperand{true}, nil
+	case p.consume("false"):
+		return literalOperand{false}, nil
+	case p.consume("null"):
+		return literalOperand{nil}, nil
+	}
+	return nil, p.errorf("unexpected %q", p.peek())
+}
@@ -0,0 +1,136 @@
+package whatever
+
+import (
+	"fmt"
+	"testing"
+)
+
+var storeBody = []byte(`{
+	"store": {
+		"book": [
+			{"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+			{"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+			{"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+			{"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+		],
+		"bicycle": {"color": "red", "price": 399}
+	},
+	"items": [
+		{"name": "a", "qty": 1, "price": 10},
+		{"name": "b", "qty": 2, "price": 20},
+		{"name": "c", "qty": 3, "price": 30}
+	],
+	"o.k": {"it's": 1}
+}`)
+
+func TestParams_Query(t *testing.T) {
+	p := parse(storeBody)
+	cases := map[string][]string{
+		`$.store.book[*].author`: {
+			`$['store']['book'][0]['author']`,
+			`$['store']['book'][1]['author']`,
+			`$['store']['book'][2]['author']`,
+			`$['store']['book'][3]['author']`,
+		},
+		`$..author`: {
+			`$['store']['book'][0]['author']`,
+			`$['store']['book'][1]['author']`,
+			`$['store']['book'][2]['author']`,
+			`$['store']['book'][3]['author']`,
+		},
+		`$.store.*`: {
+			`$['store']['bicycle']`,
+			`$['store']['book']`,
+		},
+		`$.store..price`: {
+			`$['store']['bicycle']['price']`,
+			`$['store']['book'][0]['price']`,
+			`$['store']['book'][1]['price']`,
+			`$['store']['book'][2]['price']`,
+			`$['store']['book'][3]['price']`,
+		},
+		`$..book[2]`:   {`$['store']['book'][2]`},
+		`$..book[-1]`:  {`$['store']['book'][3]`},
+		`$..book[0,1]`: {`$['store']['book'][0]`, `$['store']['book'][1]`},
+		`$..book[:2]`:  {`$['store']['book'][0]`, `$['store']['book'][1]`},
+		`$..book[::-2]`: {
+			`$['store']['book'][3]`,
+			`$['store']['book'][1]`,
+		},
+		`$..book[?@.isbn]`: {
+			`$['store']['book'][2]`,
+			`$['store']['book'][3]`,
+		},
+		`$..book[?@.price<10].title`: {
+			`$['store']['book'][0]['title']`,
+			`$['store']['book'][2]['title']`,
+		},
+		`$.items[?@.qty > 1].price`: {
+			`$['items'][1]['price']`,
+			`$['items'][2]['price']`,
+		},
+		`$.items[?@.qty > 1 && @.name != 'c'].name`: {`$['items'][1]['name']`},
+		`$.items[?(@.qty == 1 || @.qty == 3)].name`: {
+			`$['items'][0]['name']`,
+			`$['items'][2]['name']`,
+		},
+		`$.items[?!(@.qty >= 2)].name`:                    {`$['items'][0]['name']`},
+		`$.items[?@.price == $.store.bicycle.price].name`: {},
+		`$.items[?@.missing == null]`:                     {},
+		`$['o.k']["it's"]`:                                {`$['o.k']['it\'s']`},
+		`$.missing`:                                       {},
+	}
+
+	for expr, expected := range cases {
+		matches, err := p.Query(expr)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+
+		var got []string
+		for _, m := range matches {
+			got = append(got, m.Path)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) && !(len(got) == 0 && len(expected) == 0) {
+			wrong(t, fmt.Sprintf("Query(%s)", expr), expected, got)
+		}
+	}
+}
+
+func TestParams_Query_values(t *testing.T) {
+	p := parse(storeBody)
+	p.Add("nested", Params{"list": []interface{}{map[string]interface{}{"x": 1}}})
+
+	matches, err := p.Query("$.nested.list[0].x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matches) != 1 || matches[0].Value != 1 {
+		wrong(t, "Query", 1, matches)
+	}
+
+	if matches[0].Pointer != "/nested/list/0/x" {
+		wrong(t, "Query", "/nested/list/0/x", matches[0].Pointer)
+	}
+}
+
+func TestParams_Query_errors(t *testing.T) {
+	p := Params{}
+	for _, expr := range []string{
+		"",
+		"store",
+		"$.",
+		"$[",
+		"$['unterminated]",
+		"$[?@.a ==]",
+		"$[?1]",
+		"$[1:2:3:4]",
+		"$.a b",
+	} {
+		if _, err := p.Query(expr); err == nil {
+			wrong(t, "Query", "an error for "+expr, nil)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package whatever
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// RedactRule reports whether the value under
+// the path should be masked by Redacted.
+type RedactRule func(path Path, value interface{}) bool
+
+// RedactPaths masks the values under the paths in dotted
+// notation. The paths can contain wildcards as with Pick:
+//
+//	whatever.RedactPaths("db.password", "users.*.token")
+func RedactPaths(paths ...string) RedactRule {
+	patterns := splitPatterns(paths)
+	return func(path Path, value interface{}) bool {
+		return matchAny(path, patterns)
+	}
+}
+
+// RedactKeys masks the values under the keys that match
+// any of the patterns at any depth. The patterns use the
+// syntax of path.Match and ignore the case:
+//
+//	whatever.RedactKeys("password", "*token*", "*_key")
+func RedactKeys(patterns ...string) RedactRule {
+	lower := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		lower[i] = strings.ToLower(pattern)
+	}
+	return func(p Path, value interface{}) bool {
+		if len(p) == 0 {
+			return false
+		}
+		key, ok := p[len(p)-1].(string)
+		if !ok {
+			return false
+		}
+		key = strings.ToLower(key)
+		for _, pattern := range lower {
+			if matched, _ := path.Match(pattern, key); matched {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// RedactPathRegexp masks the values whose
+// paths in dotted notation match re.
+func RedactPathRegexp(re *regexp.Regexp) RedactRule {
+	return func(path Path, value interface{}) bool {
+		return len(path) > 0 && re.MatchString(path.String())
+	}
+}
+
+// RedactValueRegexp masks the string values that match re.
+func RedactValueRegexp(re *regexp.Regexp) RedactRule {
+	return func(path Path, value interface{}) bool {
+		s, ok := value.(string)
+		return ok && re.MatchString(s)
+	}
+}
+
+// RedactCardNumbers masks the values that look like payment
+// card numbers: 13 to 19 digits, optionally separated by spaces
+// or dashes, that pass the Luhn check. Both strings and
+// integers are checked.
+func RedactCardNumbers() RedactRule {
+	return func(path Path, value interface{}) bool {
+		switch v := value.(type) {
+		case string:
+			return isCardNumber(v)
+		case int, int64, uint, uint64:
+			return isCardNumber(fmt.Sprint(v))
+		case float64:
+			return v == float64(int64(v)) && isCardNumber(strconv.FormatInt(int64(v), 10))
+		}
+		return false
+	}
+}
+
+func isCardNumber(s string) bool {
+	digits := make([]int, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = append(digits, int(r-'0'))
+		case r == ' ' || r == '-':
+		default:
+			return false
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// Redacted returns a copy of the Params structure where every
+// value matched by any of the rules is replaced with the string
+// "[REDACTED]". A matched map or slice is replaced as a whole.
+// The Secret values are always replaced. The receiver is never
+// modified, so the result is safe to log:
+//
+//	log.Printf("request: %v", p.Redacted(
+//		whatever.RedactKeys("password", "*token*"),
+//		whatever.RedactCardNumbers(),
+//	))
+func (p Params) Redacted(rules ...RedactRule) Params {
+	return redactValue(nil, p, rules).(Params)
+}
+
+func redactValue(path Path, value interface{}, rules []RedactRule) interface{} {
+	if _, ok := value.(Secret); ok {
+		return Redacted
+	}
+	if len(path) > 0 {
+		for _, rule := range rules {
+			if rule(path, value) {
+				return Redacted
+			}
+		}
+	}
+
+	switch val := value.(type) {
+	case Params:
+		result := make(Params, len(val))
+		for k, el := range val {
+			result[k] = redactValue(path.Append(k), el, rules)
+		}
+		return result
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for k, el := range val {
+			result[k] = redactValue(path.Append(k), el, rules)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, el := range val {
+			result[i] = redactValue(path.Append(i), el, rules)
+		}
+		return result
+	}
+	return value
+}
+
+var redactionPolicy atomic.Value
+
+// SetRedactionPolicy registers the rules that are applied
+// whenever a Params structure is formatted with fmt or String,
+// so the Params can be logged without leaking sensitive values.
+// Calling it without rules removes the policy. The policy is
+// global for the package and safe to change concurrently.
+func SetRedactionPolicy(rules ...RedactRule) {
+	redactionPolicy.Store(append([]RedactRule(nil), rules...))
+}
+
+func redactionRules() []RedactRule {
+	rules, _ := redactionPolicy.Load().([]RedactRule)
+	return rules
+}
+
+// String returns the Params formatted as with fmt.Sprint,
+// with the redaction policy applied if there is one.
+func (p Params) String() string {
+	return fmt.Sprint(p)
+}
+
+// Format implements fmt.Formatter. Without a redaction policy
+// the Params are formatted as a regular map. With a policy they
+// are redacted first and the nested Params are formatted as
+// map[string]interface{}, so the rules apply to the full paths.
+func (p Params) Format(f fmt.State, verb rune) {
+	var value interface{} = map[string]interface{}(p)
+	if rules := redactionRules(); len(rules) > 0 {
+		value = plainMaps(redactValue(nil, p, rules))
+	}
+
+	formatted := fmt.Sprintf(formatDirective(f, verb), value)
+	if verb == 'v' && f.Flag('#') {
+		formatted = "whatever.Params" + strings.TrimPrefix(formatted, "map[string]interface {}")
+	}
+	fmt.Fprint(f, formatted)
+}
+
+// plainMaps converts the Params in v to map[string]interface{}.
+func plainMaps(v interface{}) interface{} {
+	switch val := v.(type) {
+	case Params:
+		result := make(map[string]interface{}, len(val))
+		for k, el := range val {
+			result[k] = plainMaps(el)
+		}
+		return result
+	case map[string]interface{}:
+		for k, el := range val {
+			val[k] = plainMaps(el)
+		}
+		return val
+	case []interface{}:
+		for i, el := range val {
+			val[i] = plainMaps(el)
+		}
+		return val
+	}
+	return v
+}
+
+// formatDirective rebuilds the formatting directive
+// with the flags, the width and the precision of f.
+func formatDirective(f fmt.State, verb rune) string {
+	var b strings.Builder
+	b.WriteByte('%')
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b.WriteRune(flag)
+		}
+	}
+	if width, ok := f.Width(); ok {
+		b.WriteString(strconv.Itoa(width))
+	}
+	if precision, ok := f.Precision(); ok {
+		b.WriteByte('.')
+		b.WriteString(strconv.Itoa(precision))
+	}
+	b.WriteRune(verb)
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package whatever
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var sensitive = Params{
+	"user":     "admin",
+	"password": "hunter2",
+	"db":       Params{"host": "localhost", "password": "s3cr3t"},
+	"auth":     map[string]interface{}{"AccessToken": "abc", "expires": 3600},
+	"payment":  Params{"card": "4111 1111 1111 1111", "amount": int64(4111111111111112)},
+	"numbers":  []interface{}{int64(4012888888881881), "5555-5555-5555-4444", "1234"},
+	"api":      Params{"key": Secret("key"), "internal": Params{"id": "x"}},
+	"note":     "call 555-0100",
+}
+
+func TestRedacted(t *testing.T) {
+	redacted := sensitive.Redacted(
+		RedactPaths("*.password"),
+		RedactKeys("*token*"),
+		RedactPathRegexp(regexp.MustCompile(`^api\.internal$`)),
+		RedactValueRegexp(regexp.MustCompile(`\d{3}-\d{4}`)),
+		RedactCardNumbers(),
+	)
+
+	expected := Params{
+		"user":     "admin",
+		"password": "hunter2",
+		"db":       Params{"host": "localhost", "password": Redacted},
+		"auth":     map[string]interface{}{"AccessToken": Redacted, "expires": 3600},
+		"payment":  Params{"card": Redacted, "amount": int64(4111111111111112)},
+		"numbers":  []interface{}{Redacted, Redacted, "1234"},
+		"api":      Params{"key": Redacted, "internal": Redacted},
+		"note":     Redacted,
+	}
+	if !Equal(expected, redacted) {
+		t.Errorf("Redacted was incorrect: %v", Differences(expected, redacted))
+	}
+
+	if sensitive.GetP("db").GetString("password") != "s3cr3t" {
+		t.Error("Redacted modified the receiver")
+	}
+}
+
+func TestRedactionPolicy(t *testing.T) {
+	p := Params{"user": "admin", "db": Params{"password": "s3cr3t"}}
+
+	if got := fmt.Sprintf("%v", p); got != "map[db:map[password:s3cr3t] user:admin]" {
+		wrong(t, "Format", "map[db:map[password:s3cr3t] user:admin]", got)
+	}
+	if got := fmt.Sprintf("%#v", Params{"a": 1}); got != `whatever.Params{"a":1}` {
+		wrong(t, "Format", `whatever.Params{"a":1}`, got)
+	}
+
+	SetRedactionPolicy(RedactKeys("password"))
+	defer SetRedactionPolicy()
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%20v"} {
+		if out := fmt.Sprintf(format, p); strings.Contains(out, "s3cr3t") {
+			t.Errorf("fmt.Sprintf(%q) leaked the password: %s", format, out)
+		}
+	}
+
+	if got := p.String(); got != "map[db:map[password:[REDACTED]] user:admin]" {
+		wrong(t, "String", "map[db:map[password:[REDACTED]] user:admin]", got)
+	}
+
+	if p.GetP("db").GetString("password") != "s3cr3t" {
+		t.Error("the redaction policy modified the Params")
+	}
+}
@@ -0,0 +1,383 @@
+package whatever
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Secret is a sensitive string value. It is redacted when it
+// is formatted with fmt, encoded as JSON, YAML or TOML or
+// printed in the changes of Compare, so the resolved secrets
+// don't end up in the logs. Value returns the actual string.
+type Secret string
+
+// Redacted is the replacement of the sensitive values.
+const Redacted = "[REDACTED]"
+
+// Value returns the actual value of the secret.
+func (s Secret) Value() string {
+	return string(s)
+}
+
+// String returns the redacted form of the secret.
+func (s Secret) String() string {
+	return Redacted
+}
+
+// GoString returns the redacted form of the secret for %#v.
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", Redacted)
+}
+
+// Format formats the redacted form of the secret for all verbs.
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, s.GoString())
+		return
+	}
+	if verb == 'q' {
+		fmt.Fprintf(f, "%q", Redacted)
+		return
+	}
+	fmt.Fprint(f, Redacted)
+}
+
+// MarshalText encodes the redacted form of the secret.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// GetSecret returns the Secret with the specified key.
+// Returns an empty Secret if there is no Secret with
+// the provided key. Use Value to get its contents.
+func (p Params) GetSecret(key string) Secret {
+	if val, ok := p[key]; ok {
+		if s, ok := val.(Secret); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// SecretResolver resolves the secret references of one provider.
+// The reference is parsed as URL, e.g. secret://vault/db#password
+// or file:///run/secrets/db. The implementations should stop
+// when the context is done.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// SecretResolverFunc is a function that implements SecretResolver.
+type SecretResolverFunc func(ctx context.Context, ref *url.URL) (string, error)
+
+// ResolveSecret calls f(ctx, ref).
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, ref *url.URL) (string, error) {
+	return f(ctx, ref)
+}
+
+// SecretsOption configures Secrets.
+type SecretsOption func(*Secrets)
+
+// SecretTimeout limits the time for resolving a single secret.
+// There is no limit other than the context by default.
+func SecretTimeout(d time.Duration) SecretsOption {
+	return func(s *Secrets) {
+		s.timeout = d
+	}
+}
+
+// SecretCacheTTL sets how long the resolved secrets are cached.
+// They are cached until Purge by default and a negative ttl
+// disables the caching.
+func SecretCacheTTL(ttl time.Duration) SecretsOption {
+	return func(s *Secrets) {
+		s.ttl = ttl
+	}
+}
+
+// Secrets resolves the secret references with the registered
+// resolvers and caches the results. It is safe for concurrent use.
+//
+// A reference is a string value in one of the forms
+//
+//	secret://<provider>/<path>#<key>
+//	<provider>://<path>#<key>
+//
+// where provider is the name the resolver was registered with.
+// The path of the secret:// references is passed to the resolvers
+// without the leading slash, e.g. "db" for secret://vault/db.
+// No providers are registered by default, as the configuration
+// could then read any file or environment variable. Register
+// FileSecretResolver and EnvSecretResolver to opt in:
+//
+//	secrets.Register("file", whatever.FileSecretResolver("/run/secrets"))
+//	secrets.Register("env", whatever.EnvSecretResolver())
+type Secrets struct {
+	timeout time.Duration
+	ttl     time.Duration
+
+	mu        sync.Mutex
+	resolvers map[string]SecretResolver
+	cache     map[string]cachedSecret
+}
+
+type cachedSecret struct {
+	value   Secret
+	expires time.Time
+}
+
+// NewSecrets returns Secrets without any providers.
+func NewSecrets(opts ...SecretsOption) *Secrets {
+	s := &Secrets{
+		resolvers: map[string]SecretResolver{},
+		cache:     map[string]cachedSecret{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register registers r for the provider with the given name,
+// replacing any resolver with the same name.
+func (s *Secrets) Register(provider string, r SecretResolver) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resolvers[provider] = r
+}
+
+// Purge removes all cached secrets.
+func (s *Secrets) Purge() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = map[string]cachedSecret{}
+}
+
+// IsReference reports whether value is a reference
+// to a provider with a registered resolver.
+func (s *Secrets) IsReference(value string) bool {
+	_, _, err := s.resolver(value)
+	return err == nil
+}
+
+// Resolve resolves a single secret reference.
+func (s *Secrets) Resolve(ctx context.Context, ref string) (Secret, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[ref]
+	s.mu.Unlock()
+	if ok && (cached.expires.IsZero() || time.Now().Before(cached.expires)) {
+		return cached.value, nil
+	}
+
+	resolver, u, err := s.resolver(ref)
+	if err != nil {
+		return "", err
+	}
+
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	type result struct {
+		value string
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := resolver.ResolveSecret(ctx, u)
+		done <- result{value, err}
+	}()
+
+	// The resolvers that don't watch the context
+	// can't hold the caller longer than the timeout.
+	var r result
+	select {
+	case r = <-done:
+	case <-ctx.Done():
+		r.err = ctx.Err()
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+
+	secret := Secret(r.value)
+	if s.ttl >= 0 {
+		entry := cachedSecret{value: secret}
+		if s.ttl > 0 {
+			entry.expires = time.Now().Add(s.ttl)
+		}
+		s.mu.Lock()
+		s.cache[ref] = entry
+		s.mu.Unlock()
+	}
+	return secret, nil
+}
+
+// resolver returns the resolver for the reference and the
+// reference as URL. The secret:// references are rewritten
+// to the scheme of their provider.
+func (s *Secrets) resolver(ref string) (SecretResolver, *url.URL, error) {
+	if !strings.Contains(ref, "://") {
+		return nil, nil, fmt.Errorf("%q is not a secret reference", ref)
+	}
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	provider := u.Scheme
+	if provider == "secret" {
+		provider = u.Host
+		rewritten := *u
+		rewritten.Scheme, rewritten.Host = provider, ""
+		rewritten.Path = strings.TrimPrefix(u.Path, "/")
+		u = &rewritten
+	}
+
+	s.mu.Lock()
+	resolver, ok := s.resolvers[provider]
+	s.mu.Unlock()
+	if !ok {
+		return nil, nil, fmt.Errorf("no secret resolver for %q", provider)
+	}
+	return resolver, u, nil
+}
+
+// ResolveSecrets replaces all secret references in the Params
+// structure with their values as Secret, so they are redacted
+// when the Params are printed or encoded:
+//
+//	secrets := whatever.NewSecrets(whatever.SecretTimeout(5 * time.Second))
+//	secrets.Register("vault", vaultResolver)
+//	err := p.ResolveSecrets(ctx, secrets)
+//	password := p.GetP("db").GetSecret("password").Value()
+//
+// The strings with schemes of unknown providers, like
+// https://example.com, are left as they are, except for the
+// secret:// references which have to be resolved. If there is
+// an error, it contains the path of the failing value and the
+// Params structure is not modified.
+func (p Params) ResolveSecrets(ctx context.Context, s *Secrets) error {
+	result := copyValue(p).(Params)
+	err := result.Walk(func(path Path, value interface{}) error {
+		ref, ok := value.(string)
+		if !ok || !(strings.HasPrefix(ref, "secret://") || s.IsReference(ref)) {
+			return nil
+		}
+
+		secret, err := s.Resolve(ctx, ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		return result.SetPointer(path.Pointer(), secret)
+	})
+	if err != nil {
+		return err
+	}
+
+	for k := range p {
+		delete(p, k)
+	}
+	for k, v := range result {
+		p[k] = v
+	}
+	return nil
+}
+
+// FileSecretResolver returns a SecretResolver that reads the
+// secrets from the files in the root directory, e.g. file://db
+// or secret://file/db for root/db. The absolute paths and the
+// paths that leave root, directly or through symbolic links,
+// are rejected. An empty root is the
+// working directory. The trailing newline of the contents is
+// removed. With a fragment, as in file://db.json#password, the
+// file is decoded as JSON or as .env file and the fragment is
+// the dotted key of the secret in it.
+func FileSecretResolver(root string) SecretResolver {
+	return SecretResolverFunc(func(ctx context.Context, ref *url.URL) (string, error) {
+		name := ref.Host + ref.Path
+		if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+			return "", fmt.Errorf("the secret file %s should be relative to the root", name)
+		}
+		clean := filepath.Clean(name)
+		if outside(clean) {
+			return "", fmt.Errorf("the secret file %s is outside of the root", name)
+		}
+		path := filepath.Join(root, clean)
+
+		// The symbolic links are resolved, so a link in
+		// root can't point to a file outside of it.
+		resolved, err := resolvePath(path)
+		if err != nil {
+			return "", err
+		}
+		base, err := resolvePath(root)
+		if err != nil {
+			return "", err
+		}
+		if rel, err := filepath.Rel(base, resolved); err != nil || outside(rel) {
+			return "", fmt.Errorf("the secret file %s is outside of the root", name)
+		}
+
+		body, err := os.ReadFile(resolved)
+		if err != nil {
+			return "", err
+		}
+		if ref.Fragment == "" {
+			return strings.TrimRight(string(body), "\r\n"), nil
+		}
+
+		var p Params
+		if json.Unmarshal(body, &p) != nil {
+			if p, err = NewFromDotenv(body); err != nil {
+				return "", err
+			}
+		}
+
+		value, ok := lookup(p, ref.Fragment)
+		if !ok {
+			return "", fmt.Errorf("the key %s is missing in %s", ref.Fragment, path)
+		}
+		return stringify(value), nil
+	})
+}
+
+// outside reports whether the clean relative path leaves its base.
+func outside(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// resolvePath returns the absolute path without symbolic links.
+func resolvePath(path string) (string, error) {
+	if path == "" {
+		path = "."
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(resolved)
+}
+
+// EnvSecretResolver returns a SecretResolver that reads the
+// secrets from environment variables, e.g. env://DB_PASSWORD
+// or secret://env/DB_PASSWORD.
+func EnvSecretResolver() SecretResolver {
+	return SecretResolverFunc(func(ctx context.Context, ref *url.URL) (string, error) {
+		name := strings.Trim(ref.Host+ref.Path, "/")
+		if value, ok := os.LookupEnv(name); ok {
+			return value, nil
+		}
+		return "", fmt.Errorf("the environment variable %s is not set", name)
+	})
+}
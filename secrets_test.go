@@ -0,0 +1,186 @@
+package whatever
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSecretRedaction(t *testing.T) {
+	p := Params{"user": "admin", "password": Secret("s3cr3t")}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x"} {
+		if out := fmt.Sprintf(format, p); strings.Contains(out, "s3cr3t") {
+			t.Errorf("fmt.Sprintf(%q) leaked the secret: %s", format, out)
+		}
+	}
+
+	encoded, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"password":"[REDACTED]","user":"admin"}` {
+		wrong(t, "json.Marshal", `{"password":"[REDACTED]","user":"admin"}`, string(encoded))
+	}
+
+	if got := p.GetSecret("password").Value(); got != "s3cr3t" {
+		wrong(t, "GetSecret", "s3cr3t", got)
+	}
+	if got := p.GetSecret("user"); got != "" {
+		wrong(t, "GetSecret", "", got)
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "db"), []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "db"), []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api.json"), []byte(`{"keys": {"token": "json-secret"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "db"), filepath.Join(dir, "sub", "link")); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(t.TempDir(), "outside")
+	if err := os.WriteFile(outside, []byte("leaked"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("WHATEVER_SECRET_TEST", "env-secret")
+	defer os.Unsetenv("WHATEVER_SECRET_TEST")
+
+	calls := 0
+	secrets := NewSecrets()
+	secrets.Register("file", FileSecretResolver(dir))
+	secrets.Register("env", EnvSecretResolver())
+	secrets.Register("vault", SecretResolverFunc(func(ctx context.Context, ref *url.URL) (string, error) {
+		calls++
+		return "vault:" + ref.Path + "#" + ref.Fragment, nil
+	}))
+
+	p := Params{
+		"db":       Params{"password": "file://sub/./db"},
+		"relative": "file://db",
+		"link":     "file://sub/link",
+		"token":    "secret://file/api.json#keys.token",
+		"env":      "env://WHATEVER_SECRET_TEST",
+		"vault":    []interface{}{"secret://vault/db#password", "secret://vault/db#password"},
+		"site":     "https://example.com",
+	}
+	if err := p.ResolveSecrets(context.Background(), secrets); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Params{
+		"db":       Params{"password": Secret("file-secret")},
+		"relative": Secret("file-secret"),
+		"link":     Secret("file-secret"),
+		"token":    Secret("json-secret"),
+		"env":      Secret("env-secret"),
+		"vault":    []interface{}{Secret("vault:db#password"), Secret("vault:db#password")},
+		"site":     "https://example.com",
+	}
+	if !Equal(expected, p) {
+		t.Errorf("ResolveSecrets was incorrect: %v", Differences(expected, p))
+	}
+
+	if calls != 1 {
+		wrong(t, "ResolveSecrets cache", 1, calls)
+	}
+
+	if _, err := secrets.Resolve(context.Background(), "file://escape"); err == nil || !strings.Contains(err.Error(), "outside of the root") {
+		wrong(t, "Secrets.Resolve", "the secret file escape is outside of the root", err)
+	}
+
+	secrets.Purge()
+	if _, err := secrets.Resolve(context.Background(), "secret://vault/db#password"); err != nil || calls != 2 {
+		wrong(t, "Secrets.Purge", 2, calls)
+	}
+}
+
+func TestResolveSecretsErrors(t *testing.T) {
+	secrets := NewSecrets(SecretTimeout(20*time.Millisecond), SecretCacheTTL(-1))
+	secrets.Register("slow", SecretResolverFunc(func(ctx context.Context, ref *url.URL) (string, error) {
+		time.Sleep(time.Second)
+		return "late", nil
+	}))
+
+	p := Params{"a": "ok", "b": Params{"slow": "secret://slow/value"}}
+	err := p.ResolveSecrets(context.Background(), secrets)
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.HasPrefix(err.Error(), "b.slow: ") {
+		wrong(t, "ResolveSecrets", "b.slow: context deadline exceeded", err)
+	}
+	if p.GetP("b").GetString("slow") != "secret://slow/value" {
+		t.Error("ResolveSecrets modified the Params on error")
+	}
+
+	secrets.Register("file", FileSecretResolver(t.TempDir()))
+	secrets.Register("env", EnvSecretResolver())
+	for _, ref := range []string{"secret://unknown/value", "env://WHATEVER_MISSING_VARIABLE", "file://missing/file"} {
+		if err := (Params{"key": ref}).ResolveSecrets(context.Background(), secrets); err == nil {
+			t.Errorf("ResolveSecrets expected an error for %s", ref)
+		}
+	}
+}
+
+func TestNewSecrets_noProviders(t *testing.T) {
+	os.Setenv("WHATEVER_SECRET_TEST", "env-secret")
+	defer os.Unsetenv("WHATEVER_SECRET_TEST")
+
+	p := Params{"env": "env://WHATEVER_SECRET_TEST", "file": "file://secrets.go"}
+	if err := p.ResolveSecrets(context.Background(), NewSecrets()); err != nil {
+		t.Fatal(err)
+	}
+	if p.GetString("env") != "env://WHATEVER_SECRET_TEST" || p.GetString("file") != "file://secrets.go" {
+		wrong(t, "ResolveSecrets", "unresolved references", p)
+	}
+
+	if err := (Params{"env": "secret://env/WHATEVER_SECRET_TEST"}).ResolveSecrets(context.Background(), NewSecrets()); err == nil {
+		t.Error("ResolveSecrets expected an error for an unregistered provider")
+	}
+}
+
+func TestFileSecretResolver_root(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "secrets")
+	if err := os.Mkdir(root, 0700); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(filepath.Dir(root), "outside")
+	if err := os.WriteFile(outside, []byte("leaked"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	secrets := NewSecrets()
+	secrets.Register("file", FileSecretResolver(root))
+	refs := []string{
+		"file:///" + outside,
+		"file://" + outside,
+		"file://../outside",
+		"file://../../x",
+		"file://a/../../outside",
+		"secret://file/../outside",
+		"secret://file//" + outside,
+	}
+	for _, ref := range refs {
+		secret, err := secrets.Resolve(context.Background(), ref)
+		if err == nil || !strings.Contains(err.Error(), "root") {
+			t.Errorf("Secrets.Resolve(%q) expected a root error, got %q, %v", ref, secret.Value(), err)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package whatever
+
+import (
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// SyncParams is a Params structure guarded by a sync.RWMutex
+// so it can be shared between goroutines. It has the same getters
+// as Params and the mutating methods take the write lock.
+//
+// The getters that return reference values (GetP, GetI, GetSlice)
+// return deep copies, so the result can be used freely without
+// holding any lock.
+//
+// For compound operations that need to read and write atomically
+// use Update or CompareAndSwap.
+type SyncParams struct {
+	mu     sync.RWMutex
+	params Params
+}
+
+// NewSyncParams returns a SyncParams that wraps a deep copy
+// of the passed Params. If p is nil, an empty Params is used.
+func NewSyncParams(p Params) *SyncParams {
+	params, _ := copyValue(p).(Params)
+	if params == nil {
+		params = Params{}
+	}
+	return &SyncParams{params: params}
+}
+
+// Snapshot returns a deep copy of the underlying Params.
+func (s *SyncParams) Snapshot() Params {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyValue(s.params).(Params)
+}
+
+// View calls fn with the underlying Params while holding
+// the read lock. The Params must not be modified or retained
+// after fn returns.
+func (s *SyncParams) View(fn func(Params)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s.params)
+}
+
+// Update calls fn with the underlying Params while holding
+// the write lock, so all changes made by fn are applied atomically.
+// The Params must not be retained after fn returns.
+func (s *SyncParams) Update(fn func(Params)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.params)
+}
+
+// CompareAndSwap sets the value under the dotted path to new
+// only if the current value is deeply equal to old.
+// A missing path is matched by a nil old value. An existing
+// value on the path that is not a map, e.g. a string or a slice,
+// is never replaced, so the swap fails for "name.first" when
+// "name" is a string. Returns true if the value was swapped.
+func (s *SyncParams) CompareAndSwap(path string, old, new interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !canStore(s.params, path) {
+		return false
+	}
+	current, _ := lookup(s.params, path)
+	if !reflect.DeepEqual(current, old) {
+		return false
+	}
+
+	store(s.params, path, copyValue(new))
+	return true
+}
+
+// Add adds a copy of the value under the key to the structure.
+// Returns true if an existing value was overwritten.
+func (s *SyncParams) Add(key string, value interface{}) bool {
+	value = copyValue(value)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.params.Add(key, value)
+}
+
+// Delete deletes a top-level key from the structure
+// and returns it's value.
+func (s *SyncParams) Delete(key string) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.params.Delete(key)
+}
+
+// Merge sets copies of all top-level fields from set to the structure.
+func (s *SyncParams) Merge(set map[string]interface{}) {
+	set, _ = copyValue(set).(map[string]interface{})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.params.Merge(set)
+}
+
+// Defaults sets copies of the top-level fields from set
+// that are missing in the structure.
+func (s *SyncParams) Defaults(set map[string]interface{}) {
+	set, _ = copyValue(set).(map[string]interface{})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.params.Defaults(set)
+}
+
+// Empty checks if the structure is empty.
+func (s *SyncParams) Empty() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.Empty()
+}
+
+// Required works as Params.Required.
+func (s *SyncParams) Required(keys ...string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.Required(keys...)
+}
+
+// Keys returns the top-level keys.
+func (s *SyncParams) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.Keys()
+}
+
+// NestedKeys returns all keys in dotted notation.
+func (s *SyncParams) NestedKeys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.NestedKeys()
+}
+
+// URLValues works as Params.URLValues.
+func (s *SyncParams) URLValues(prefix, suffix string) url.Values {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.URLValues(prefix, suffix)
+}
+
+// GetP returns a deep copy of the nested Params with the specified key.
+func (s *SyncParams) GetP(key string) Params {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyValue(s.params.GetP(key)).(Params)
+}
+
+// GetI returns a deep copy of the value with the specified key.
+func (s *SyncParams) GetI(key string) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyValue(s.params.GetI(key))
+}
+
+// Get works as Params.Get.
+func (s *SyncParams) Get(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.Get(key)
+}
+
+// GetString works as Params.GetString.
+func (s *SyncParams) GetString(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetString(key)
+}
+
+// GetInt works as Params.GetInt.
+func (s *SyncParams) GetInt(key string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetInt(key)
+}
+
+// GetInt8 works as Params.GetInt8.
+func (s *SyncParams) GetInt8(key string) int8 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetInt8(key)
+}
+
+// GetInt64 works as Params.GetInt64.
+func (s *SyncParams) GetInt64(key string) int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetInt64(key)
+}
+
+// GetFloat works as Params.GetFloat.
+func (s *SyncParams) GetFloat(key string) float32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetFloat(key)
+}
+
+// GetFloat32 works as Params.GetFloat32.
+func (s *SyncParams) GetFloat32(key string) float32 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetFloat32(key)
+}
+
+// GetFloat64 works as Params.GetFloat64.
+func (s *SyncParams) GetFloat64(key string) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetFloat64(key)
+}
+
+// GetTime works as Params.GetTime.
+func (s *SyncParams) GetTime(key string) time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetTime(key)
+}
+
+// GetSlice returns a deep copy of the slice with the specified key.
+func (s *SyncParams) GetSlice(key string) []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if slice := s.params.GetSlice(key); slice != nil {
+		return copyValue(slice).([]interface{})
+	}
+	return nil
+}
+
+// GetSliceStrings works as Params.GetSliceStrings.
+func (s *SyncParams) GetSliceStrings(key string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetSliceStrings(key)
+}
+
+// GetSliceInts works as Params.GetSliceInts.
+func (s *SyncParams) GetSliceInts(key string) []int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.params.GetSliceInts(key)
+}
@@ -0,0 +1,154 @@
+package whatever
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestNewSyncParams(t *testing.T) {
+	p := Params{"nested": Params{"one": 1}}
+	s := NewSyncParams(p)
+
+	// The wrapped Params is a copy.
+	p.GetP("nested").Add("one", 10)
+	if got := s.GetP("nested").GetInt("one"); got != 1 {
+		wrong(t, "NewSyncParams", 1, got)
+	}
+
+	if !NewSyncParams(nil).Empty() {
+		wrong(t, "NewSyncParams", true, false)
+	}
+}
+
+func TestSyncParams_Getters(t *testing.T) {
+	s := NewSyncParams(parse(body))
+
+	if got := s.Get("string"); got != "test" {
+		wrong(t, "SyncParams.Get", "test", got)
+	}
+
+	if got := s.GetInt("int"); got != -10 {
+		wrong(t, "SyncParams.GetInt", -10, got)
+	}
+
+	if got := s.GetSliceStrings("arrayStrings"); !equalSlicesStrings([]string{"one", "two", "three"}, got) {
+		wrong(t, "SyncParams.GetSliceStrings", []string{"one", "two", "three"}, got)
+	}
+
+	if err := s.Required("nestedParams.params2.three"); err != nil {
+		wrong(t, "SyncParams.Required", nil, err)
+	}
+
+	// The returned nested values are copies.
+	s.GetP("nestedParams").Add("one", 100)
+	s.GetSlice("arrayInts")[0] = 100
+	if got := s.GetP("nestedParams").GetInt("one"); got != 1 {
+		wrong(t, "SyncParams.GetP", 1, got)
+	}
+	if got := s.GetSliceInts("arrayInts")[0]; got != 1 {
+		wrong(t, "SyncParams.GetSlice", 1, got)
+	}
+}
+
+func TestSyncParams_CompareAndSwap(t *testing.T) {
+	s := NewSyncParams(Params{"nested": Params{"one": 1}})
+
+	if s.CompareAndSwap("nested.one", 2, 3) {
+		wrong(t, "CompareAndSwap", false, true)
+	}
+
+	if !s.CompareAndSwap("nested.one", 1, 3) {
+		wrong(t, "CompareAndSwap", true, false)
+	}
+
+	if got := s.GetP("nested").GetInt("one"); got != 3 {
+		wrong(t, "CompareAndSwap", 3, got)
+	}
+
+	if !s.CompareAndSwap("missing.key", nil, "new") {
+		wrong(t, "CompareAndSwap", true, false)
+	}
+
+	if err := s.Required("missing.key"); err != nil {
+		wrong(t, "CompareAndSwap", nil, err)
+	}
+}
+
+func TestSyncParams_CompareAndSwapIntermediates(t *testing.T) {
+	s := NewSyncParams(Params{"name": "x", "list": []interface{}{1, 2}})
+
+	if s.CompareAndSwap("name.first", nil, "y") {
+		wrong(t, "CompareAndSwap", false, true)
+	}
+
+	if s.CompareAndSwap("list.0", nil, 9) {
+		wrong(t, "CompareAndSwap", false, true)
+	}
+
+	expected := Params{"name": "x", "list": []interface{}{1, 2}}
+	if got := s.Snapshot(); !reflect.DeepEqual(expected, got) {
+		wrong(t, "CompareAndSwap", expected, got)
+	}
+}
+
+func TestSyncParams_copiesInput(t *testing.T) {
+	s := NewSyncParams(Params{})
+	added := Params{"host": "a"}
+	merged := map[string]interface{}{"list": []interface{}{1}}
+	defaults := map[string]interface{}{"nested": Params{"one": 1}}
+	swapped := Params{"value": "x"}
+
+	s.Add("added", added)
+	s.Merge(merged)
+	s.Defaults(defaults)
+	s.CompareAndSwap("swapped", nil, swapped)
+
+	added["host"] = "b"
+	merged["list"].([]interface{})[0] = 2
+	defaults["nested"].(Params)["one"] = 2
+	swapped["value"] = "y"
+
+	expected := Params{
+		"added":   Params{"host": "a"},
+		"list":    []interface{}{1},
+		"nested":  Params{"one": 1},
+		"swapped": Params{"value": "x"},
+	}
+	if got := s.Snapshot(); !Equal(expected, got) {
+		wrong(t, "SyncParams", expected, got)
+	}
+}
+
+func TestSyncParams_concurrent(t *testing.T) {
+	s := NewSyncParams(Params{"counter": 0})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key%d", i)
+			s.Add(key, i)
+			s.Get(key)
+			s.Keys()
+			s.NestedKeys()
+			s.Snapshot()
+			s.Update(func(p Params) {
+				p["counter"] = p.GetInt("counter") + 1
+			})
+			s.Merge(Params{"merged": i})
+			s.Delete(key)
+		}(i)
+	}
+	wg.Wait()
+
+	if got := s.GetInt("counter"); got != 50 {
+		wrong(t, "SyncParams.Update", 50, got)
+	}
+
+	if got := len(s.Keys()); got != 2 {
+		wrong(t, "SyncParams.Keys", 2, got)
+	}
+}
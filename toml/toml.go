@@ -0,0 +1,91 @@
+// Package toml decodes and encodes whatever.Params as TOML.
+// It lives in its own package, so the whatever package has no
+// third-party dependencies. Importing it registers the .toml
+// extension for whatever.FileSource:
+//
+//	import _ "github.com/ndyakov/whatever/toml"
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ndyakov/whatever"
+)
+
+func init() {
+	whatever.RegisterFormat(".toml", Decode)
+}
+
+// Decode receives a slice of bytes that should be a TOML
+// document and returns Params structure for it and an error if
+// there was one while decoding. Tables become nested Params and
+// arrays of tables become slices of Params. Datetimes and dates
+// become time.Time, so they can be read with GetTime. Integers
+// are decoded as int64 and floats as float64.
+//
+// The decoding errors contain the line and the column
+// of the problem in the document.
+func Decode(tomlBody []byte) (whatever.Params, error) {
+	var v map[string]interface{}
+	if _, err := toml.Decode(string(tomlBody), &v); err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, positionError{parseErr}
+		}
+		return nil, err
+	}
+
+	return fromTOML(v).(whatever.Params), nil
+}
+
+// positionError reports a parse error with its position
+// once, the cause is available through errors.As.
+type positionError struct {
+	err toml.ParseError
+}
+
+func (e positionError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.err.Position.Line, e.err.Position.Col, e.err.Message)
+}
+
+func (e positionError) Unwrap() error {
+	return e.err
+}
+
+// Encode encodes the Params structure as a TOML document.
+// Nested Params become tables and slices of Params become
+// arrays of tables.
+func Encode(p whatever.Params) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(map[string]interface{}(p)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func fromTOML(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		result := make(whatever.Params, len(val))
+		for k, el := range val {
+			result[k] = fromTOML(el)
+		}
+		return result
+	case []map[string]interface{}:
+		result := make([]interface{}, len(val))
+		for i, el := range val {
+			result[i] = fromTOML(el)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, el := range val {
+			result[i] = fromTOML(el)
+		}
+		return result
+	}
+	return v
+}
@@ -0,0 +1,127 @@
+package toml
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ndyakov/whatever"
+)
+
+var tomlBody = []byte(`
+title = "example"
+created = 1979-05-27T07:32:00Z
+birthday = 1979-05-27
+
+[database]
+host = "localhost"
+ports = [8000, 8001]
+ratio = 0.5
+
+[[products]]
+name = "Hammer"
+sku = 738594937
+
+[[products]]
+name = "Nail"
+`)
+
+func TestDecode(t *testing.T) {
+	p, err := Decode(tomlBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Required("title", "database.host", "database.ports"); err != nil {
+		wrong(t, "Required", nil, err)
+	}
+
+	if got := p.GetP("database").GetSliceInts("ports"); !reflect.DeepEqual([]int{8000, 8001}, got) {
+		wrong(t, "Decode", []int{8000, 8001}, got)
+	}
+
+	products := p.GetSlice("products")
+	if len(products) != 2 {
+		t.Fatalf("expected 2 products, got %#v", products)
+	}
+
+	if product, ok := products[0].(whatever.Params); !ok || product.GetInt("sku") != 738594937 {
+		wrong(t, "Decode", "Params with sku", products[0])
+	}
+
+	expected := time.Date(1979, time.May, 27, 7, 32, 0, 0, time.UTC)
+	if got := p.GetTime("created"); !got.Equal(expected) {
+		wrong(t, "GetTime", expected, got)
+	}
+
+	if got := p.GetTime("birthday"); got.Year() != 1979 || got.Day() != 27 {
+		wrong(t, "GetTime", "1979-05-27", got)
+	}
+}
+
+func TestDecode_error(t *testing.T) {
+	_, err := Decode([]byte("a = 1\nb = = 2\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2, column 5: ") {
+		t.Fatalf("expected an error at line 2, column 5, got %v", err)
+	}
+
+	var parseErr toml.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected the toml.ParseError to be wrapped, got %#v", err)
+	}
+	if expected := "line 2, column 5: " + parseErr.Message; err.Error() != expected {
+		wrong(t, "Decode", expected, err.Error())
+	}
+}
+
+func TestEncode(t *testing.T) {
+	p := whatever.Params{
+		"name":     "test",
+		"count":    int64(2),
+		"nested":   whatever.Params{"list": []interface{}{int64(1), int64(2)}},
+		"products": []interface{}{whatever.Params{"name": "a"}, whatever.Params{"name": "b"}},
+	}
+
+	body, err := Encode(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := Decode(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !whatever.Equal(p, decoded) {
+		wrong(t, "Encode", p, whatever.Differences(p, decoded))
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(file, []byte("[db]\nhost = \"db.local\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := whatever.FileSource(file).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.GetP("db").GetString("host"); got != "db.local" {
+		wrong(t, "FileSource", "db.local", got)
+	}
+}
+
+func wrong(t *testing.T, method string, expected, got interface{}) {
+	t.Errorf(
+		"%s was incorrect.\n Expected: %#v, Got: %#v",
+		method,
+		expected,
+		got,
+	)
+}
@@ -0,0 +1,109 @@
+package whatever
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SkipDir can be returned from the pre-order WalkFunc to
+// skip the children of the current object or slice.
+// When returned for any other value it is ignored.
+var SkipDir = errors.New("skip this object")
+
+// Path is the location of a value in a Params structure.
+// The elements are string keys for objects and int indexes
+// for slices.
+type Path []interface{}
+
+// String returns the path in the dotted notation used
+// by Required and NestedKeys, e.g. "items.0.price".
+func (p Path) String() string {
+	parts := make([]string, len(p))
+	for i, el := range p {
+		parts[i] = fmt.Sprint(el)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Pointer returns the path as a JSON Pointer, e.g. "/items/0/price".
+func (p Path) Pointer() string {
+	tokens := make([]string, len(p))
+	for i, el := range p {
+		tokens[i] = fmt.Sprint(el)
+	}
+	return formatPointer(tokens)
+}
+
+// Append returns a new Path with the element appended.
+// The receiver is never modified, so the result can be
+// retained safely.
+func (p Path) Append(el interface{}) Path {
+	result := make(Path, len(p)+1)
+	copy(result, p)
+	result[len(p)] = el
+	return result
+}
+
+// WalkFunc is called by Walk for every visited value.
+// Returning an error other than SkipDir stops the walk
+// and the error is returned by Walk.
+type WalkFunc func(path Path, value interface{}) error
+
+// Walk calls fn for every value in the Params structure
+// in pre-order - objects and slices are visited before
+// their children. Nested Params, map[string]interface{}
+// and []interface{} values are descended into. Object keys
+// are visited in sorted order, so the walk is deterministic.
+//
+// If fn returns SkipDir for an object or a slice,
+// its children are not visited.
+func (p Params) Walk(fn WalkFunc) error {
+	return p.WalkWith(fn, nil)
+}
+
+// WalkWith works as Walk, but accepts two functions - pre is
+// called before the children of a value are visited and post
+// is called after that. Either of them can be nil.
+//
+// If pre returns SkipDir for an object or a slice, both
+// its children and the post call for it are skipped.
+func (p Params) WalkWith(pre, post WalkFunc) error {
+	for _, k := range sortedKeys(p) {
+		if err := walk(Path{k}, p[k], pre, post); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walk(path Path, value interface{}, pre, post WalkFunc) error {
+	if pre != nil {
+		if err := pre(path, value); err == SkipDir {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+
+	if m, ok := toMap(value); ok {
+		for _, k := range sortedKeys(m) {
+			if err := walk(path.Append(k), m[k], pre, post); err != nil {
+				return err
+			}
+		}
+	} else if s, ok := value.([]interface{}); ok {
+		for i, el := range s {
+			if err := walk(path.Append(i), el, pre, post); err != nil {
+				return err
+			}
+		}
+	}
+
+	if post != nil {
+		if err := post(path, value); err != nil && err != SkipDir {
+			return err
+		}
+	}
+	return nil
+}
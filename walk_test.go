@@ -0,0 +1,111 @@
+package whatever
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var walkParams = Params{
+	"b": 1,
+	"a": Params{
+		"list": []interface{}{
+			"x",
+			map[string]interface{}{"y": 2},
+		},
+	},
+	"c": map[string]interface{}{"d": 3},
+}
+
+func TestParams_Walk(t *testing.T) {
+	var got []string
+	err := walkParams.Walk(func(path Path, value interface{}) error {
+		got = append(got, path.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"a", "a.list", "a.list.0", "a.list.1", "a.list.1.y", "b", "c", "c.d"}
+	if fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "Walk", expected, got)
+	}
+}
+
+func TestParams_Walk_skipDir(t *testing.T) {
+	var got []string
+	walkParams.Walk(func(path Path, value interface{}) error {
+		got = append(got, path.Pointer())
+		if path.String() == "a.list" || path.String() == "b" {
+			return SkipDir
+		}
+		return nil
+	})
+
+	expected := []string{"/a", "/a/list", "/b", "/c", "/c/d"}
+	if fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "Walk", expected, got)
+	}
+}
+
+func TestParams_Walk_error(t *testing.T) {
+	stop := errors.New("stop")
+	var visited int
+	err := walkParams.Walk(func(path Path, value interface{}) error {
+		visited++
+		if path.String() == "a.list.0" {
+			return stop
+		}
+		return nil
+	})
+
+	if err != stop {
+		wrong(t, "Walk", stop, err)
+	}
+
+	if visited != 3 {
+		wrong(t, "Walk", 3, visited)
+	}
+}
+
+func TestParams_WalkWith(t *testing.T) {
+	var got []string
+	pre := func(path Path, value interface{}) error {
+		got = append(got, "pre:"+path.String())
+		if path.String() == "a" {
+			return SkipDir
+		}
+		return nil
+	}
+	post := func(path Path, value interface{}) error {
+		got = append(got, "post:"+path.String())
+		return nil
+	}
+
+	if err := walkParams.WalkWith(pre, post); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"pre:a", "pre:b", "post:b", "pre:c", "pre:c.d", "post:c.d", "post:c"}
+	if fmt.Sprint(expected) != fmt.Sprint(got) {
+		wrong(t, "WalkWith", expected, got)
+	}
+}
+
+func TestPath(t *testing.T) {
+	path := Path{"a/b", 0, "c.d"}
+
+	if got := path.String(); got != "a/b.0.c.d" {
+		wrong(t, "Path.String", "a/b.0.c.d", got)
+	}
+
+	if got := path.Pointer(); got != "/a~1b/0/c.d" {
+		wrong(t, "Path.Pointer", "/a~1b/0/c.d", got)
+	}
+
+	appended := path[:1].Append("x")
+	if path[1] != 0 || appended.String() != "a/b.x" {
+		wrong(t, "Path.Append", "a/b.x", appended.String())
+	}
+}
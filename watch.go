@@ -0,0 +1,387 @@
+package whatever
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// WatchOption configures a Watcher.
+type WatchOption func(*watchOptions)
+
+type watchOptions struct {
+	files    []string
+	interval time.Duration
+	polling  bool
+	validate func(Params) error
+	onError  func(error)
+}
+
+// WatchFiles sets the files that trigger a reload when they
+// change. By default these are the files of the FileSource
+// sources of the Config.
+func WatchFiles(paths ...string) WatchOption {
+	return func(o *watchOptions) {
+		o.files = append(o.files, paths...)
+	}
+}
+
+// PollInterval sets how often the files are checked when
+// they are polled. The default is one second. NewWatcher
+// returns an error if d is not positive.
+func PollInterval(d time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.interval = d
+	}
+}
+
+// UsePolling forces polling of the files even
+// if the platform supports file notifications.
+func UsePolling() WatchOption {
+	return func(o *watchOptions) {
+		o.polling = true
+	}
+}
+
+// Validate sets a function that has to accept the loaded
+// Params before they become the active snapshot. It is
+// called after the validation of the required keys.
+func Validate(fn func(Params) error) WatchOption {
+	return func(o *watchOptions) {
+		o.validate = fn
+	}
+}
+
+// OnError sets a function that receives the errors of the
+// reloads triggered by file changes. The previous snapshot
+// stays active after an error.
+func OnError(fn func(error)) WatchOption {
+	return func(o *watchOptions) {
+		o.onError = fn
+	}
+}
+
+// Watcher keeps the Params of a Config up to date with the files
+// on disk. It uses inotify on Linux and polling elsewhere. Every
+// reload loads all sources, validates the result and then swaps
+// the active snapshot atomically, so Params never returns a half
+// loaded configuration:
+//
+//	watcher, err := whatever.NewWatcher(config, whatever.OnError(log.Println))
+//	if err != nil {
+//		return err
+//	}
+//	defer watcher.Close()
+//	watcher.Subscribe(func(changes whatever.Changes, p whatever.Params) {
+//		log.Printf("config changed:\n%s", changes)
+//	})
+//
+// The snapshots are shared with all readers and subscribers
+// and should not be modified. The Config should not be loaded
+// elsewhere while it is watched.
+type Watcher struct {
+	config  *Config
+	options watchOptions
+	current atomic.Value
+
+	reloadMu    sync.Mutex
+	mu          sync.Mutex
+	subscribers map[int]func(Changes, Params)
+	nextID      int
+	pending     []notification
+	delivering  bool
+
+	notifier  notifier
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewWatcher loads and validates the Config and starts
+// watching its files. It returns an error if the initial
+// load fails or if the options are invalid.
+func NewWatcher(config *Config, opts ...WatchOption) (*Watcher, error) {
+	w := &Watcher{
+		config:      config,
+		options:     watchOptions{interval: time.Second},
+		subscribers: map[int]func(Changes, Params){},
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&w.options)
+	}
+	if w.options.interval <= 0 {
+		return nil, fmt.Errorf("the poll interval should be positive, got %v", w.options.interval)
+	}
+
+	if w.options.files == nil {
+		sources, _ := config.stack()
+		for _, source := range sources {
+			if path := sourcePath(source); path != "" {
+				w.options.files = append(w.options.files, path)
+			}
+		}
+	}
+
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+
+	if len(w.options.files) == 0 {
+		close(w.done)
+		return w, nil
+	}
+
+	var err error
+	if !w.options.polling {
+		w.notifier, err = newFileNotifier(w.options.files)
+	}
+	if w.options.polling || err != nil {
+		w.notifier = newPoller(w.options.files, w.options.interval)
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Params returns the active snapshot.
+func (w *Watcher) Params() Params {
+	return w.current.Load().(Params)
+}
+
+// Subscribe registers fn to be called with the changed paths
+// and the new snapshot after every reload that changed something.
+// The subscribers are called one by one in the order of the
+// reloads, by the goroutine of one of the reloads.
+// The returned function cancels the subscription.
+func (w *Watcher) Subscribe(fn func(changes Changes, p Params)) (cancel func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = fn
+	return func() {
+		w.mu.Lock()
+		delete(w.subscribers, id)
+		w.mu.Unlock()
+	}
+}
+
+// Reload loads and validates the Config and, if it is valid,
+// makes it the active snapshot and notifies the subscribers.
+// It is called automatically when the watched files change,
+// but can be called manually as well, e.g. on SIGHUP. The
+// subscribers can call Reload too, the subscribers are then
+// notified of that reload after the current notification.
+func (w *Watcher) Reload() error {
+	w.reloadMu.Lock()
+	changes, p, err := w.reload()
+	if err == nil && len(changes) > 0 {
+		// The notification is queued before the next
+		// reload can start, so the order is kept.
+		w.mu.Lock()
+		w.pending = append(w.pending, notification{changes, p})
+		w.mu.Unlock()
+	}
+	w.reloadMu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	w.deliver()
+	return nil
+}
+
+func (w *Watcher) reload() (Changes, Params, error) {
+	p, origins, err := w.config.load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if w.options.validate != nil {
+		if err := w.options.validate(p); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	old, _ := w.current.Load().(Params)
+	w.config.swap(p, origins)
+	w.current.Store(p)
+	if old == nil {
+		return nil, p, nil
+	}
+	return Compare(old, p), p, nil
+}
+
+type notification struct {
+	changes Changes
+	params  Params
+}
+
+// deliver notifies the subscribers of the pending reloads in
+// order. Only one goroutine delivers at a time, the others leave
+// their reloads to it, so the subscribers can call Reload.
+func (w *Watcher) deliver() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.delivering {
+		return
+	}
+
+	w.delivering = true
+	for len(w.pending) > 0 {
+		n := w.pending[0]
+		w.pending = w.pending[1:]
+
+		ids := sortedIDs(w.subscribers)
+		subscribers := make([]func(Changes, Params), len(ids))
+		for i, id := range ids {
+			subscribers[i] = w.subscribers[id]
+		}
+
+		w.mu.Unlock()
+		for _, fn := range subscribers {
+			fn(n.changes, n.params)
+		}
+		w.mu.Lock()
+	}
+	w.delivering = false
+}
+
+// Close stops watching the files. The last snapshot stays
+// available through Params. Close waits for the subscribers
+// that are being notified of a reload triggered by the files.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		if w.notifier != nil {
+			err = w.notifier.Close()
+		}
+		<-w.done
+	})
+	return err
+}
+
+// watchDebounce is the quiet period after a file event before the
+// reload, so the several events of a single save cause one reload.
+const watchDebounce = 50 * time.Millisecond
+
+func (w *Watcher) run() {
+	defer close(w.done)
+
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-w.stop:
+			return
+		case _, ok := <-w.notifier.Events():
+			if !ok {
+				return
+			}
+			timer = time.After(watchDebounce)
+		case <-timer:
+			timer = nil
+			if err := w.Reload(); err != nil && w.options.onError != nil {
+				w.options.onError(err)
+			}
+		}
+	}
+}
+
+func sortedIDs(m map[int]func(Changes, Params)) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// notifier sends a value on Events
+// when one of the watched files changes.
+type notifier interface {
+	Events() <-chan struct{}
+	Close() error
+}
+
+type poller struct {
+	events chan struct{}
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime int64
+}
+
+func newPoller(paths []string, interval time.Duration) *poller {
+	p := &poller{
+		events: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	states := make([]fileState, len(paths))
+	for i, path := range paths {
+		states[i] = statFile(path)
+	}
+
+	go func() {
+		defer close(p.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+			}
+
+			changed := false
+			for i, path := range paths {
+				if state := statFile(path); state != states[i] {
+					states[i], changed = state, true
+				}
+			}
+			if changed {
+				notify(p.events)
+			}
+		}
+	}()
+	return p
+}
+
+func (p *poller) Events() <-chan struct{} {
+	return p.events
+}
+
+func (p *poller) Close() error {
+	close(p.stop)
+	<-p.done
+	return nil
+}
+
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, size: info.Size(), modTime: info.ModTime().UnixNano()}
+}
+
+// notify sends on events without blocking, a pending
+// event already means that a reload will follow.
+func notify(events chan struct{}) {
+	select {
+	case events <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,104 @@
+//go:build linux
+
+package whatever
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// inotify watches the directories of the files, so the
+// files replaced by rename (as many editors and deploy
+// tools do) are still noticed.
+type inotify struct {
+	file   *os.File
+	names  map[string]bool
+	events chan struct{}
+	done   chan struct{}
+}
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_CREATE | syscall.IN_DELETE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+func newFileNotifier(paths []string) (notifier, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	n := &inotify{
+		// A non-blocking descriptor is handled by the runtime
+		// poller, so Close interrupts the pending Read.
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		names:  map[string]bool{},
+		events: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	watched := map[int32]string{}
+	dirs := map[string]bool{}
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			n.file.Close()
+			return nil, err
+		}
+		n.names[abs] = true
+
+		dir := filepath.Dir(abs)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		wd, err := syscall.InotifyAddWatch(fd, dir, inotifyMask)
+		if err != nil {
+			n.file.Close()
+			return nil, &os.PathError{Op: "inotify_add_watch", Path: dir, Err: err}
+		}
+		watched[int32(wd)] = dir
+	}
+
+	go n.read(watched)
+	return n, nil
+}
+
+func (n *inotify) read(watched map[int32]string) {
+	defer close(n.done)
+	defer close(n.events)
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		count, err := n.file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		changed := false
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= count; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			start := offset + syscall.SizeofInotifyEvent
+			name := string(bytes.TrimRight(buf[start:start+int(event.Len)], "\x00"))
+			offset = start + int(event.Len)
+
+			if n.names[filepath.Join(watched[event.Wd], name)] {
+				changed = true
+			}
+		}
+		if changed {
+			notify(n.events)
+		}
+	}
+}
+
+func (n *inotify) Events() <-chan struct{} {
+	return n.events
+}
+
+func (n *inotify) Close() error {
+	err := n.file.Close()
+	<-n.done
+	return err
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package whatever
+
+import "errors"
+
+// newFileNotifier is available only on Linux,
+// the other platforms fall back to polling.
+func newFileNotifier(paths []string) (notifier, error) {
+	return nil, errors.New("file notifications are not supported")
+}
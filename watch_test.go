@@ -0,0 +1,173 @@
+package whatever
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func testWatcher(t *testing.T, opts ...WatchOption) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.json")
+	writeConfigFile(t, file, `{"db": {"host": "a", "port": 1}}`)
+
+	errs := make(chan error, 1)
+	config := NewConfig(StaticSource("defaults", Params{"name": "example"}), FileSource(file)).Require("db.host")
+	opts = append(opts, OnError(func(err error) { errs <- err }), Validate(func(p Params) error {
+		if p.GetP("db").GetInt("port") < 0 {
+			return errors.New("invalid port")
+		}
+		return nil
+	}))
+
+	watcher, err := NewWatcher(config, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	if got := watcher.Params().GetP("db").GetString("host"); got != "a" {
+		wrong(t, "Watcher.Params", "a", got)
+	}
+
+	updates := make(chan Changes, 1)
+	watcher.Subscribe(func(changes Changes, p Params) { updates <- changes })
+	canceled := watcher.Subscribe(func(changes Changes, p Params) { t.Error("canceled subscriber was called") })
+	canceled()
+
+	writeConfigFile(t, file, `{"db": {"host": "b", "port": 1}}`)
+	select {
+	case changes := <-updates:
+		expected := "~ db.host: \"a\" -> \"b\"\n"
+		if changes.String() != expected {
+			wrong(t, "Watcher.Subscribe", expected, changes.String())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watcher did not reload the changed file")
+	}
+
+	if got, _ := config.Origin("db.host"); got != "file:"+file {
+		wrong(t, "Config.Origin", "file:"+file, got)
+	}
+
+	writeConfigFile(t, file, `{"db": {"host": "c", "port": -1}}`)
+	select {
+	case err := <-errs:
+		if err.Error() != "invalid port" {
+			wrong(t, "Watcher.OnError", "invalid port", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watcher did not report the invalid file")
+	}
+
+	if got := watcher.Params().GetP("db").GetString("host"); got != "b" {
+		wrong(t, "Watcher.Params", "b", got)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	testWatcher(t)
+}
+
+func TestWatcherPolling(t *testing.T) {
+	testWatcher(t, UsePolling(), PollInterval(10*time.Millisecond))
+}
+
+func TestWatcherPollInterval(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.json")
+	writeConfigFile(t, file, `{"name": "a"}`)
+
+	for _, d := range []time.Duration{0, -time.Second} {
+		watcher, err := NewWatcher(NewConfig(FileSource(file)), UsePolling(), PollInterval(d))
+		if err == nil {
+			watcher.Close()
+			t.Errorf("NewWatcher expected an error for the poll interval %v", d)
+		}
+	}
+}
+
+func TestWatcherReloadFromSubscriber(t *testing.T) {
+	var mu sync.Mutex
+	value := 0
+	config := NewConfig(SourceFunc("counter", func() (Params, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		value++
+		return Params{"value": value}, nil
+	}))
+
+	watcher, err := NewWatcher(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	var seen []int
+	watcher.Subscribe(func(changes Changes, p Params) {
+		seen = append(seen, p.GetInt("value"))
+		if len(seen) < 3 {
+			if err := watcher.Reload(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- watcher.Reload()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watcher.Reload from a subscriber deadlocked")
+	}
+
+	if expected := []int{2, 3, 4}; !reflect.DeepEqual(expected, seen) {
+		wrong(t, "Watcher.Subscribe", expected, seen)
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	value := "a"
+	config := NewConfig(SourceFunc("dynamic", func() (Params, error) {
+		return Params{"value": value}, nil
+	})).Require("value")
+
+	watcher, err := NewWatcher(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	value = ""
+	if err := watcher.Reload(); err == nil {
+		t.Error("Watcher.Reload expected an error for a missing required key")
+	}
+
+	value = "b"
+	if err := watcher.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := watcher.Params().GetString("value"); got != "b" {
+		wrong(t, "Watcher.Reload", "b", got)
+	}
+
+	if _, err := NewWatcher(NewConfig(FileSource("missing.json"))); err == nil {
+		t.Error("NewWatcher expected an error for a missing file")
+	}
+}
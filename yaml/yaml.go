@@ -0,0 +1,102 @@
+// Package yaml decodes and encodes whatever.Params as YAML.
+// It lives in its own package, so the whatever package has no
+// third-party dependencies. Importing it registers the .yaml and
+// .yml extensions for whatever.FileSource:
+//
+//	import _ "github.com/ndyakov/whatever/yaml"
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ndyakov/whatever"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+func init() {
+	whatever.RegisterFormat(".yaml", Decode)
+	whatever.RegisterFormat(".yml", Decode)
+}
+
+// Decode receives a slice of bytes that should be a YAML
+// document with a mapping at the top level and returns Params
+// structure for it and an error if there was one while decoding.
+// Nested mappings become Params, integers stay int, anchors and
+// aliases are resolved and timestamps become time.Time, so they
+// can be read with GetTime. Only the first document of a stream
+// is decoded, use DecodeStream for all of them.
+func Decode(yamlBody []byte) (whatever.Params, error) {
+	var v interface{}
+	if err := yamlv3.Unmarshal(yamlBody, &v); err != nil {
+		return nil, err
+	}
+	return document(v)
+}
+
+// DecodeStream works as Decode, but decodes all documents
+// in a multi-document stream separated by "---".
+func DecodeStream(yamlBody []byte) ([]whatever.Params, error) {
+	var result []whatever.Params
+	decoder := yamlv3.NewDecoder(bytes.NewReader(yamlBody))
+	for {
+		var v interface{}
+		err := decoder.Decode(&v)
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := document(v)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %v", len(result), err)
+		}
+		result = append(result, p)
+	}
+}
+
+// Encode encodes the Params structure as a YAML document.
+func Encode(p whatever.Params) ([]byte, error) {
+	return yamlv3.Marshal(map[string]interface{}(p))
+}
+
+func document(v interface{}) (whatever.Params, error) {
+	if v == nil {
+		return whatever.Params{}, nil
+	}
+
+	p, ok := fromYAML(v).(whatever.Params)
+	if !ok {
+		return nil, fmt.Errorf("the YAML document should be a mapping, got %T", v)
+	}
+	return p, nil
+}
+
+// fromYAML converts the decoded YAML mappings to Params. The
+// mappings with non-string keys use the string form of the keys.
+func fromYAML(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		result := make(whatever.Params, len(val))
+		for k, el := range val {
+			result[k] = fromYAML(el)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(whatever.Params, len(val))
+		for k, el := range val {
+			result[fmt.Sprint(k)] = fromYAML(el)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, el := range val {
+			result[i] = fromYAML(el)
+		}
+		return result
+	}
+	return v
+}
@@ -0,0 +1,149 @@
+package yaml
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ndyakov/whatever"
+)
+
+var yamlBody = []byte(`
+defaults: &defaults
+  adapter: postgres
+  port: 5432
+database:
+  <<: *defaults
+  host: localhost
+  ratio: 1.5
+replica: *defaults
+created: 2015-02-20T21:22:23Z
+birthday: 2002-12-14
+tags: [one, two]
+codes:
+  1: one
+`)
+
+func TestDecode(t *testing.T) {
+	p, err := Decode(yamlBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db := p.GetP("database")
+	if db.Get("adapter") != "postgres" || db.Get("host") != "localhost" {
+		wrong(t, "Decode", "postgres@localhost", db)
+	}
+
+	if _, ok := db["port"].(int); !ok {
+		wrong(t, "Decode", "int", db["port"])
+	}
+
+	if _, ok := db["ratio"].(float64); !ok {
+		wrong(t, "Decode", "float64", db["ratio"])
+	}
+
+	if _, ok := p["replica"].(whatever.Params); !ok {
+		wrong(t, "Decode", "Params", p["replica"])
+	}
+
+	if got := p.GetP("codes").Get("1"); got != "one" {
+		wrong(t, "Decode", "one", got)
+	}
+
+	expected := time.Date(2015, time.February, 20, 21, 22, 23, 0, time.UTC)
+	if created, ok := p["created"].(time.Time); !ok || !created.Equal(expected) {
+		wrong(t, "Decode", expected, p["created"])
+	}
+
+	expected = time.Date(2002, time.December, 14, 0, 0, 0, 0, time.UTC)
+	if birthday, ok := p["birthday"].(time.Time); !ok || !birthday.Equal(expected) {
+		wrong(t, "Decode", expected, p["birthday"])
+	}
+	if got := p.GetTime("birthday"); !got.Equal(expected) {
+		wrong(t, "GetTime", expected, got)
+	}
+
+	if got := p.GetSliceStrings("tags"); !reflect.DeepEqual([]string{"one", "two"}, got) {
+		wrong(t, "Decode", []string{"one", "two"}, got)
+	}
+
+	if err := p.Required("database.host", "replica.port"); err != nil {
+		wrong(t, "Required", nil, err)
+	}
+}
+
+func TestDecode_errors(t *testing.T) {
+	for _, doc := range []string{"- a\n- b\n", "key: [unclosed\n", "scalar"} {
+		if _, err := Decode([]byte(doc)); err == nil {
+			wrong(t, "Decode", "an error for "+doc, nil)
+		}
+	}
+
+	p, err := Decode([]byte(""))
+	if err != nil || !p.Empty() {
+		wrong(t, "Decode", whatever.Params{}, p)
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	docs, err := DecodeStream([]byte("a: 1\n---\nb: 2\n---\nc: 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(docs) != 3 || docs[0].GetInt("a") != 1 || docs[2].GetInt("c") != 3 {
+		wrong(t, "DecodeStream", "3 documents", docs)
+	}
+
+	if _, err := DecodeStream([]byte("a: 1\n---\n- list\n")); err == nil {
+		wrong(t, "DecodeStream", "an error", nil)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	p := whatever.Params{
+		"name":   "test",
+		"nested": whatever.Params{"count": 2, "list": []interface{}{1, "two"}},
+	}
+
+	body, err := Encode(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := Decode(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !whatever.Equal(p, decoded) {
+		wrong(t, "Encode", p, whatever.Differences(p, decoded))
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(file, []byte("db:\n  host: db.local\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := whatever.FileSource(file).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.GetP("db").GetString("host"); got != "db.local" {
+		wrong(t, "FileSource", "db.local", got)
+	}
+}
+
+func wrong(t *testing.T, method string, expected, got interface{}) {
+	t.Errorf(
+		"%s was incorrect.\n Expected: %#v, Got: %#v",
+		method,
+		expected,
+		got,
+	)
+}